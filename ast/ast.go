@@ -0,0 +1,273 @@
+package ast
+
+import (
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Node interface {
+	Span() token.Span
+	node()
+}
+
+type Symbol struct {
+	Loc       token.Span
+	Namespace string
+	Name      string
+}
+
+func (n *Symbol) Span() token.Span {
+	return n.Loc
+}
+
+func (n *Symbol) String() string {
+	if n.Namespace != "" {
+		return n.Namespace + "/" + n.Name
+	}
+	return n.Name
+}
+
+type Keyword struct {
+	Loc          token.Span
+	Namespace    string
+	Name         string
+	AutoResolved bool
+}
+
+func (n *Keyword) Span() token.Span {
+	return n.Loc
+}
+
+func (n *Keyword) String() string {
+	prefix := ":"
+	if n.AutoResolved {
+		prefix = "::"
+	}
+	if n.Namespace != "" {
+		return prefix + n.Namespace + "/" + n.Name
+	}
+	return prefix + n.Name
+}
+
+type Literal struct {
+	Loc   token.Span
+	Token token.Token
+	Text  string
+}
+
+func (n *Literal) Span() token.Span {
+	return n.Loc
+}
+
+type List struct {
+	Open  token.Span
+	Close token.Span
+	Elems []Node
+}
+
+func (n *List) Span() token.Span {
+	return collectionSpan(n.Open, n.Close, n.Elems)
+}
+
+type Vector struct {
+	Open  token.Span
+	Close token.Span
+	Elems []Node
+}
+
+func (n *Vector) Span() token.Span {
+	return collectionSpan(n.Open, n.Close, n.Elems)
+}
+
+type Map struct {
+	Open  token.Span
+	Close token.Span
+	Elems []Node
+}
+
+func (n *Map) Span() token.Span {
+	return collectionSpan(n.Open, n.Close, n.Elems)
+}
+
+type Set struct {
+	Open  token.Span
+	Close token.Span
+	Elems []Node
+}
+
+func (n *Set) Span() token.Span {
+	return collectionSpan(n.Open, n.Close, n.Elems)
+}
+
+type Fn struct {
+	Open  token.Span
+	Close token.Span
+	Elems []Node
+}
+
+func (n *Fn) Span() token.Span {
+	return collectionSpan(n.Open, n.Close, n.Elems)
+}
+
+type NamespacedMap struct {
+	Mark      token.Span
+	Namespace string
+	Auto      bool
+	Map       *Map
+}
+
+func (n *NamespacedMap) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Map.Span().End}
+}
+
+type Quote struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *Quote) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type SyntaxQuote struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *SyntaxQuote) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type Unquote struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *Unquote) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type UnquoteSplicing struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *UnquoteSplicing) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type Deref struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *Deref) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type VarQuote struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *VarQuote) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type Discard struct {
+	Mark token.Span
+	Form Node
+}
+
+func (n *Discard) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type ReaderConditional struct {
+	Mark     token.Span
+	Splicing bool
+	Form     Node
+}
+
+func (n *ReaderConditional) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type SymbolicValue struct {
+	Loc  token.Span
+	Name string
+}
+
+func (n *SymbolicValue) Span() token.Span {
+	return n.Loc
+}
+
+type Metadata struct {
+	Mark token.Span
+	Meta Node
+	Form Node
+}
+
+func (n *Metadata) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type Tagged struct {
+	Mark token.Span
+	Tag  *Symbol
+	Form Node
+}
+
+func (n *Tagged) Span() token.Span {
+	return token.Span{Start: n.Mark.Start, End: n.Form.Span().End}
+}
+
+type Bad struct {
+	Loc  token.Span
+	Text string
+}
+
+func (n *Bad) Span() token.Span {
+	return n.Loc
+}
+
+type Missing struct {
+	Loc token.Span
+}
+
+func (n *Missing) Span() token.Span {
+	return n.Loc
+}
+
+func (*Symbol) node()            {}
+func (*Keyword) node()           {}
+func (*Literal) node()           {}
+func (*List) node()              {}
+func (*Vector) node()            {}
+func (*Map) node()               {}
+func (*Set) node()               {}
+func (*Fn) node()                {}
+func (*NamespacedMap) node()     {}
+func (*Quote) node()             {}
+func (*SyntaxQuote) node()       {}
+func (*Unquote) node()           {}
+func (*UnquoteSplicing) node()   {}
+func (*Deref) node()             {}
+func (*VarQuote) node()          {}
+func (*Discard) node()           {}
+func (*ReaderConditional) node() {}
+func (*SymbolicValue) node()     {}
+func (*Metadata) node()          {}
+func (*Tagged) node()            {}
+func (*Bad) node()               {}
+func (*Missing) node()           {}
+
+func collectionSpan(open, close token.Span, elems []Node) token.Span {
+	end := close.End
+	if close == (token.Span{}) {
+		end = open.End
+		if len(elems) > 0 {
+			end = elems[len(elems)-1].Span().End
+		}
+	}
+	return token.Span{Start: open.Start, End: end}
+}
@@ -0,0 +1,88 @@
+package ast
+
+import (
+	"slices"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func (n *ReaderConditional) Select(features ...string) (Node, bool) {
+	list, ok := n.Form.(*List)
+	if !ok {
+		return nil, false
+	}
+	for i := 0; i+1 < len(list.Elems); i += 2 {
+		kw, ok := list.Elems[i].(*Keyword)
+		if ok && kw.Namespace == "" && !kw.AutoResolved && slices.Contains(features, kw.Name) {
+			return list.Elems[i+1], true
+		}
+	}
+	return nil, false
+}
+
+func (r *Reader) conditional() Node {
+	cond := r.readConditional()
+	if r.features == nil {
+		return cond
+	}
+	if cond.Splicing {
+		r.errorf(cond.Mark, "reader conditional splicing not allowed outside a collection")
+		return cond
+	}
+	if form, ok := cond.Select(r.features...); ok {
+		return form
+	}
+	return r.form()
+}
+
+func (r *Reader) forms() []Node {
+	if r.features == nil {
+		return []Node{r.form()}
+	}
+	switch r.tok.Kind() {
+	case token.KindReaderConditional, token.KindReaderConditionalSplicing:
+	default:
+		return []Node{r.form()}
+	}
+
+	cond := r.readConditional()
+	form, ok := cond.Select(r.features...)
+	switch {
+	case !ok:
+		return nil
+	case !cond.Splicing:
+		return []Node{form}
+	}
+	switch form := unlocated(form).(type) {
+	case *List:
+		return form.Elems
+	case *Vector:
+		return form.Elems
+	default:
+		r.errorf(form.Span(), "spliced reader conditional branch must be a list or vector")
+		return nil
+	}
+}
+
+func (r *Reader) readConditional() *ReaderConditional {
+	span := r.span
+	splicing := r.tok.Kind() == token.KindReaderConditionalSplicing
+	r.advance()
+	cond := &ReaderConditional{Mark: span, Splicing: splicing, Form: r.bare()}
+
+	switch body := cond.Form.(type) {
+	case *List:
+		if len(body.Elems)%2 != 0 {
+			r.errorf(body.Open, "reader conditional must contain an even number of forms")
+		}
+		for i := 0; i < len(body.Elems); i += 2 {
+			if kw, ok := body.Elems[i].(*Keyword); !ok || kw.Namespace != "" || kw.AutoResolved {
+				r.errorf(body.Elems[i].Span(), "reader conditional feature must be an unqualified keyword")
+			}
+		}
+	case *Bad, *Missing:
+	default:
+		r.errorf(body.Span(), "reader conditional body must be a list")
+	}
+	return cond
+}
@@ -0,0 +1,155 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReaderConditional(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		features []string
+		expected string
+		errors   []string
+	}{
+		{
+			name:     "preserved",
+			source:   "#?(:clj a :gasp b) #?@(:gasp [c])",
+			expected: "(reader-conditional (:clj a :gasp b)) (reader-conditional-splicing (:gasp [c]))",
+		},
+		{
+			name:     "selected",
+			source:   "#?(:clj a :gasp b)",
+			features: []string{"gasp", "default"},
+			expected: "b",
+		},
+		{
+			name:     "first match wins",
+			source:   "#?(:default a :gasp b)",
+			features: []string{"gasp", "default"},
+			expected: "a",
+		},
+		{
+			name:     "no match at top level",
+			source:   "#?(:clj a) b",
+			features: []string{"gasp"},
+			expected: "b",
+		},
+		{
+			name:     "no match at end of input",
+			source:   "#?(:clj a)",
+			features: []string{"gasp"},
+			expected: "",
+		},
+		{
+			name:     "no match in collection",
+			source:   "[a #?(:clj b) c #?(:cljs d)]",
+			features: []string{"gasp"},
+			expected: "[a c]",
+		},
+		{
+			name:     "no match after prefix",
+			source:   "'#?(:clj a) b",
+			features: []string{"gasp"},
+			expected: "(quote b)",
+		},
+		{
+			name:     "splicing",
+			source:   "(a #?@(:gasp [b c] :clj (d)) e)",
+			features: []string{"gasp"},
+			expected: "(a b c e)",
+		},
+		{
+			name:     "splicing list",
+			source:   "[#?@(:gasp (b c))]",
+			features: []string{"gasp"},
+			expected: "[b c]",
+		},
+		{
+			name:     "nested",
+			source:   "#?(:gasp #?(:clj a :default b))",
+			features: []string{"gasp", "default"},
+			expected: "b",
+		},
+		{
+			name:     "qualified feature never matches",
+			source:   "[#?(:x/gasp a ::gasp b :gasp c)]",
+			features: []string{"gasp"},
+			expected: "[c]",
+			errors: []string{
+				"1:5: reader conditional feature must be an unqualified keyword",
+				"1:15: reader conditional feature must be an unqualified keyword",
+			},
+		},
+		{
+			name:     "splicing at top level",
+			source:   "#?@(:gasp [a])",
+			features: []string{"gasp"},
+			expected: "(reader-conditional-splicing (:gasp [a]))",
+			errors:   []string{"1:1: reader conditional splicing not allowed outside a collection"},
+		},
+		{
+			name:     "splicing non-sequential",
+			source:   "[#?@(:gasp a)]",
+			features: []string{"gasp"},
+			expected: "[]",
+			errors:   []string{"1:12: spliced reader conditional branch must be a list or vector"},
+		},
+		{
+			name:     "odd length",
+			source:   "#?(:clj a :gasp)",
+			expected: "(reader-conditional (:clj a :gasp))",
+			errors:   []string{"1:3: reader conditional must contain an even number of forms"},
+		},
+		{
+			name:     "non-keyword feature",
+			source:   "#?(clj a)",
+			expected: "(reader-conditional (clj a))",
+			errors:   []string{"1:4: reader conditional feature must be an unqualified keyword"},
+		},
+		{
+			name:     "non-list body",
+			source:   "#?[:clj a]",
+			expected: "(reader-conditional [:clj a])",
+			errors:   []string{"1:3: reader conditional body must be a list"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.features != nil {
+				opts = append(opts, Features(tt.features...))
+			}
+			nodes, err := Read(tt.source, opts...)
+
+			var received []string
+			var list ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					received = append(received, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			requireEqual(t, fmt.Sprint(tt.errors), fmt.Sprint(received))
+		})
+	}
+}
+
+func TestReaderConditionalSelect(t *testing.T) {
+	nodes, err := Read("#?(:clj a :gasp b)")
+	requireEqual(t, nil, err)
+	cond := nodes[0].(*ReaderConditional)
+
+	form, ok := cond.Select("gasp")
+	requireEqual(t, true, ok)
+	requireEqual(t, "b", dump(form))
+
+	_, ok = cond.Select("cljs")
+	requireEqual(t, false, ok)
+}
@@ -0,0 +1,123 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func (r *Reader) attach(mark token.Span, meta, form Node) Node {
+	m := r.metaMap(meta)
+	if m == nil {
+		return &Metadata{Mark: mark, Meta: meta, Form: form}
+	}
+	if inner, ok := form.(*Metadata); ok {
+		if im, ok := inner.Meta.(*Map); ok {
+			return &Metadata{Mark: mark, Meta: mergeMeta(im, m), Form: inner.Form}
+		}
+	}
+	return &Metadata{Mark: mark, Meta: m, Form: form}
+}
+
+func (r *Reader) metaMap(meta Node) *Map {
+	span := meta.Span()
+	switch meta := meta.(type) {
+	case *Map:
+		return meta
+	case *Keyword:
+		return synthMap(span, meta, &Symbol{Loc: point(span.End), Name: "true"})
+	case *Symbol:
+		return synthMap(span, &Keyword{Loc: point(span.Start), Name: "tag"}, meta)
+	case *Literal:
+		if meta.Token.Literal().Kind() == token.LiteralKindString {
+			return synthMap(span, &Keyword{Loc: point(span.Start), Name: "tag"}, meta)
+		}
+	case *Vector:
+		return synthMap(span, &Keyword{Loc: point(span.Start), Name: "param-tags"}, meta)
+	case *Bad, *Missing:
+		return nil
+	}
+	r.errorf(span, "metadata must be a symbol, keyword, string, map or vector")
+	return nil
+}
+
+func (r *Reader) locate(n Node) Node {
+	switch n.(type) {
+	case *List, *Vector, *Map, *Set, *Fn:
+	default:
+		return n
+	}
+	at := point(n.Span().Start)
+	elems := []Node{
+		&Keyword{Loc: at, Name: "line"}, integer(at, at.Start.Line),
+		&Keyword{Loc: at, Name: "column"}, integer(at, at.Start.Column),
+	}
+	if r.file != "" {
+		elems = append(elems, &Keyword{Loc: at, Name: "file"}, str(at, r.file))
+	}
+	return &Metadata{Mark: at, Meta: synthMap(at, elems...), Form: n}
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func unlocated(n Node) Node {
+	if m, ok := n.(*Metadata); ok && synthesized(m.Mark) {
+		return m.Form
+	}
+	return n
+}
+
+func synthesized(span token.Span) bool {
+	return span.Start == span.End
+}
+
+func mergeMeta(inner, outer *Map) *Map {
+	elems := append([]Node(nil), inner.Elems...)
+	for i := 0; i+1 < len(outer.Elems); i += 2 {
+		k := metaKey(outer.Elems[i])
+		j := 0
+		for ; j+1 < len(elems); j += 2 {
+			if k != "" && metaKey(elems[j]) == k {
+				break
+			}
+		}
+		if j+1 < len(elems) {
+			elems[j], elems[j+1] = outer.Elems[i], outer.Elems[i+1]
+		} else {
+			elems = append(elems, outer.Elems[i], outer.Elems[i+1])
+		}
+	}
+	return &Map{Open: outer.Open, Close: outer.Close, Elems: elems}
+}
+
+func metaKey(n Node) string {
+	switch n := n.(type) {
+	case *Keyword:
+		return n.String()
+	case *Symbol:
+		return "'" + n.String()
+	case *Literal:
+		return n.Text
+	default:
+		return ""
+	}
+}
+
+func synthMap(span token.Span, elems ...Node) *Map {
+	return &Map{Open: point(span.Start), Close: point(span.End), Elems: elems}
+}
+
+func point(p token.Pos) token.Span {
+	return token.Span{Start: p, End: p}
+}
+
+func integer(at token.Span, n int) *Literal {
+	text := strconv.Itoa(n)
+	return &Literal{Loc: at, Token: token.NewIntegerToken(len(text), token.BaseDecimal), Text: text}
+}
+
+func str(at token.Span, s string) *Literal {
+	text := `"` + stringEscaper.Replace(s) + `"`
+	return &Literal{Loc: at, Token: token.NewStringToken(len(text)), Text: text}
+}
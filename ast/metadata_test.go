@@ -0,0 +1,158 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReaderAttachMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		file     string
+		expected string
+		errors   []string
+	}{
+		{
+			name:     "keyword",
+			source:   "^:private foo",
+			expected: "(with-meta foo {:private true})",
+		},
+		{
+			name:     "tag symbol",
+			source:   "^String foo",
+			expected: "(with-meta foo {:tag String})",
+		},
+		{
+			name:     "tag string",
+			source:   `^"[B" foo`,
+			expected: `(with-meta foo {:tag "[B"})`,
+		},
+		{
+			name:     "param tags",
+			source:   "^[long] foo",
+			expected: "(with-meta foo {:param-tags [long]})",
+		},
+		{
+			name:     "map",
+			source:   `^{:doc "x"} foo`,
+			expected: `(with-meta foo {:doc "x"})`,
+		},
+		{
+			name:     "stacked",
+			source:   "^:a ^{:b 1 :a false} ^String foo",
+			expected: "(with-meta foo {:tag String :b 1 :a true})",
+		},
+		{
+			name:     "location",
+			source:   "foo\n  (a [b])",
+			expected: "foo (with-meta (a (with-meta [b] {:line 2 :column 6})) {:line 2 :column 3})",
+		},
+		{
+			name:     "location with file",
+			source:   "#{}",
+			file:     `src\"core".clj`,
+			expected: `(with-meta #{} {:line 1 :column 1 :file "src\\\"core\".clj"})`,
+		},
+		{
+			name:     "explicit metadata wins over location",
+			source:   "^{:line 10} ^:a ()",
+			expected: "(with-meta () {:line 10 :column 17 :a true})",
+		},
+		{
+			name:     "metadata map has no location",
+			source:   "^{:m [1]} x",
+			expected: "(with-meta x {:m (with-meta [1] {:line 1 :column 6})})",
+		},
+		{
+			name:     "reader conditional body",
+			source:   "#?(:clj a)",
+			expected: "(reader-conditional (:clj a))",
+		},
+		{
+			name:     "namespaced map",
+			source:   "#:a{:b 1}",
+			expected: "(namespaced-map a {:b 1})",
+		},
+		{
+			name:     "invalid",
+			source:   "^1 foo",
+			expected: "(with-meta foo 1)",
+			errors:   []string{"1:2: metadata must be a symbol, keyword, string, map or vector"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Read(tt.source, AttachMetadata(tt.file))
+
+			var received []string
+			var list ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					received = append(received, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			requireEqual(t, fmt.Sprint(tt.errors), fmt.Sprint(received))
+		})
+	}
+}
+
+func TestReaderAttachMetadataFeatures(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"[1 #?@(:gasp [2 3]) 4]", "(with-meta [1 2 3 4] {:line 1 :column 1})"},
+		{"(#?@(:gasp (a [b])))", "(with-meta (a (with-meta [b] {:line 1 :column 15})) {:line 1 :column 1})"},
+		{"#?(:gasp [a])", "(with-meta [a] {:line 1 :column 10})"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			nodes, err := Read(tt.source, Features("gasp"), AttachMetadata(""))
+			requireEqual(t, nil, err)
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+		})
+	}
+}
+
+func TestReaderAttachMetadataSyntaxQuote(t *testing.T) {
+	nodes, err := Read("`^{:a 1} (b)", AttachMetadata("core.clj"), ExpandSyntaxQuote(InNamespace("user")))
+	requireEqual(t, nil, err)
+	requireEqual(t, "(clojure.core/with-meta (clojure.core/seq (clojure.core/concat (clojure.core/list (quote user/b)))) (clojure.core/apply clojure.core/hash-map (clojure.core/seq (clojure.core/concat (clojure.core/list :a) (clojure.core/list 1)))))", dumpNodes(nodes))
+}
+
+func TestReaderAttachMetadataSyntaxQuoteExplicitLocation(t *testing.T) {
+	nodes, err := Read("`^{:line 5} x `^{:line 5} []", AttachMetadata(""), ExpandSyntaxQuote(InNamespace("user")))
+	requireEqual(t, nil, err)
+	requireEqual(t, "(clojure.core/with-meta (quote user/x) (clojure.core/apply clojure.core/hash-map (clojure.core/seq (clojure.core/concat (clojure.core/list :line) (clojure.core/list 5))))) "+
+		"(clojure.core/with-meta (clojure.core/apply clojure.core/vector (clojure.core/seq (clojure.core/concat))) (clojure.core/apply clojure.core/hash-map (clojure.core/seq (clojure.core/concat (clojure.core/list :line) (clojure.core/list 5)))))", dumpNodes(nodes))
+}
+
+func TestReaderAttachMetadataSpan(t *testing.T) {
+	nodes, err := Read(" ^:a ^:b (c)", AttachMetadata(""))
+	requireEqual(t, nil, err)
+	requireEqual(t, "1:2-1:13", nodes[0].Span().String())
+}
+
+func TestReaderAttachMetadataValues(t *testing.T) {
+	nodes, err := Read("\n  ()", AttachMetadata(`a "b"\c.clj`))
+	requireEqual(t, nil, err)
+	elems := nodes[0].(*Metadata).Meta.(*Map).Elems
+
+	line := elems[1].(*Literal)
+	n, err := line.Token.Literal().Integer().Value(line.Text)
+	requireEqual(t, nil, err)
+	requireEqual(t, "2", n.String())
+
+	file := elems[5].(*Literal)
+	s, err := file.Token.Literal().String().Value(file.Text)
+	requireEqual(t, nil, err)
+	requireEqual(t, `a "b"\c.clj`, s)
+}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Option func(*options)
+
+type options struct {
+	token         []token.Option
+	keepDiscarded bool
+	recover       bool
+	resolver      Resolver
+	expand        bool
+	features      []string
+	metadata      bool
+	file          string
+}
+
+func TokenOptions(opts ...token.Option) Option {
+	return func(o *options) {
+		o.token = append(o.token, opts...)
+	}
+}
+
+func KeepDiscarded() Option {
+	return func(o *options) {
+		o.keepDiscarded = true
+	}
+}
+
+func Recover() Option {
+	return func(o *options) {
+		o.recover = true
+	}
+}
+
+func ExpandSyntaxQuote(r Resolver) Option {
+	return func(o *options) {
+		o.resolver = r
+		o.expand = true
+	}
+}
+
+func Features(features ...string) Option {
+	return func(o *options) {
+		o.features = append(o.features, features...)
+		if o.features == nil {
+			o.features = []string{}
+		}
+	}
+}
+
+func AttachMetadata(file string) Option {
+	return func(o *options) {
+		o.metadata = true
+		o.file = file
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,484 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Error struct {
+	Span token.Span
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %s", e.Span.Start, e.Msg)
+}
+
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+	}
+}
+
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+type Reader struct {
+	t          *token.Tokenizer
+	keep       bool
+	recover    bool
+	heuristics bool
+	started    bool
+	eof        bool
+	err        error
+	tok        token.Token
+	span       token.Span
+	text       string
+	spaced     bool
+	lineStart  bool
+	errs       ErrorList
+	diagnosed  int
+	queue      []lexeme
+	open       []token.Kind
+	expander   *Expander
+	features   []string
+	metadata   bool
+	file       string
+}
+
+type lexeme struct {
+	tok   token.Token
+	span  token.Span
+	text  string
+	diags token.Diagnostics
+	err   error
+}
+
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	o := newOptions(opts)
+	reader := &Reader{
+		t:        token.NewTokenizer(r, o.token...),
+		keep:     o.keepDiscarded,
+		recover:  o.recover,
+		features: o.features,
+		metadata: o.metadata,
+		file:     o.file,
+	}
+	if o.expand {
+		reader.expander = NewExpander(o.resolver)
+	}
+	return reader
+}
+
+func Read(src string, opts ...Option) ([]Node, error) {
+	r := NewReader(strings.NewReader(src), opts...)
+	var nodes []Node
+	var errs ErrorList
+	for {
+		node, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nodes, errs.Err()
+		}
+		var list ErrorList
+		if errors.As(err, &list) {
+			errs = append(errs, list...)
+		} else if err != nil {
+			return nodes, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+}
+
+func (r *Reader) Read() (Node, error) {
+	r.errs = nil
+	if !r.started {
+		r.started = true
+		r.advance()
+	}
+
+	var node Node
+	for node == nil {
+		for r.skipDiscarded(); r.isCloser(); r.skipDiscarded() {
+			r.errorf(r.span, "unexpected %s", r.text)
+			r.advance()
+		}
+
+		if r.eof {
+			if r.err != nil {
+				return nil, r.err
+			}
+			if len(r.errs) > 0 {
+				return nil, r.errs
+			}
+			return nil, io.EOF
+		}
+
+		r.heuristics = r.recover && !r.balanced()
+		if r.features != nil && r.tok.Kind() == token.KindReaderConditional {
+			if nodes := r.forms(); len(nodes) > 0 {
+				node = nodes[0]
+			}
+			continue
+		}
+		node = r.form()
+	}
+	if r.expander != nil {
+		var errs ErrorList
+		node, errs = r.expander.expand(node)
+		r.errs = append(r.errs, errs...)
+	}
+	if r.err != nil {
+		return node, r.err
+	}
+	return node, r.errs.Err()
+}
+
+func (r *Reader) form() Node {
+	node := r.bare()
+	if r.metadata {
+		return r.locate(node)
+	}
+	return node
+}
+
+func (r *Reader) bare() Node {
+	r.skipDiscarded()
+	if r.eof || r.isCloser() {
+		pos := r.span.Start
+		if r.eof {
+			pos = r.span.End
+		}
+		r.errorf(token.Span{Start: pos, End: pos}, "missing form")
+		if r.recover {
+			return &Missing{Loc: token.Span{Start: pos, End: pos}}
+		}
+		return &Bad{Loc: token.Span{Start: pos, End: pos}}
+	}
+
+	span, text := r.span, r.text
+	switch r.tok.Kind() {
+	case token.KindSymbol:
+		sym := r.tok.Symbol()
+		r.advance()
+		return &Symbol{Loc: span, Namespace: sym.Namespace(text), Name: sym.Name(text)}
+	case token.KindKeyword:
+		kw := r.tok.Keyword()
+		if kw.NameOffset() == len(text) {
+			r.errorf(span, "missing name in keyword")
+		}
+		r.advance()
+		return &Keyword{Loc: span, Namespace: kw.Namespace(text), Name: kw.Name(text), AutoResolved: kw.AutoResolved()}
+	case token.KindLiteral:
+		tok := r.tok
+		r.advance()
+		return &Literal{Loc: span, Token: tok, Text: text}
+	case token.KindOpenParen:
+		r.advance()
+		elems, close := r.elems(span, text, token.KindCloseParen)
+		return &List{Open: span, Close: close, Elems: elems}
+	case token.KindOpenBracket:
+		r.advance()
+		elems, close := r.elems(span, text, token.KindCloseBracket)
+		return &Vector{Open: span, Close: close, Elems: elems}
+	case token.KindOpenBrace:
+		r.advance()
+		elems, close := r.elems(span, text, token.KindCloseBrace)
+		if len(elems)%2 != 0 {
+			r.errorf(span, "map literal must contain an even number of forms")
+		}
+		return &Map{Open: span, Close: close, Elems: elems}
+	case token.KindQuote:
+		r.advance()
+		return &Quote{Mark: span, Form: r.form()}
+	case token.KindBackquote:
+		r.advance()
+		return &SyntaxQuote{Mark: span, Form: r.form()}
+	case token.KindUnquote:
+		r.advance()
+		return &Unquote{Mark: span, Form: r.form()}
+	case token.KindUnquoteSplicing:
+		r.advance()
+		return &UnquoteSplicing{Mark: span, Form: r.form()}
+	case token.KindDeref:
+		r.advance()
+		return &Deref{Mark: span, Form: r.form()}
+	case token.KindMetadata:
+		r.advance()
+		meta := r.bare()
+		form := r.form()
+		if r.metadata {
+			return r.attach(span, meta, form)
+		}
+		return &Metadata{Mark: span, Meta: meta, Form: form}
+	case token.KindOpenSet:
+		r.advance()
+		elems, close := r.elems(span, text, token.KindCloseBrace)
+		return &Set{Open: span, Close: close, Elems: elems}
+	case token.KindOpenFn:
+		r.advance()
+		elems, close := r.elems(span, text, token.KindCloseParen)
+		return &Fn{Open: span, Close: close, Elems: elems}
+	case token.KindVarQuote:
+		r.advance()
+		return &VarQuote{Mark: span, Form: r.form()}
+	case token.KindDiscard:
+		r.advance()
+		return &Discard{Mark: span, Form: r.form()}
+	case token.KindReaderConditional, token.KindReaderConditionalSplicing:
+		return r.conditional()
+	case token.KindSymbolicValue:
+		r.advance()
+		return &SymbolicValue{Loc: span, Name: text[2:]}
+	case token.KindNamespacedMap:
+		return r.namespacedMap()
+	case token.KindTag:
+		t := r.tok.Tag()
+		r.advance()
+		tag := &Symbol{Loc: token.Span{Start: advancePos(span.Start, 1), End: span.End}, Namespace: t.Namespace(text), Name: t.Name(text)}
+		return &Tagged{Mark: span, Tag: tag, Form: r.form()}
+	case token.KindDispatch, token.KindInvalid:
+		r.advance()
+		return &Bad{Loc: span, Text: text}
+	default:
+		r.errorf(span, "invalid token %q", text)
+		r.advance()
+		return &Bad{Loc: span, Text: text}
+	}
+}
+
+func (r *Reader) namespacedMap() Node {
+	mark, text := r.span, r.text
+	ns, auto := strings.CutPrefix(text[2:], ":")
+	r.advance()
+	if r.eof || r.tok.Kind() != token.KindOpenBrace {
+		r.errorf(mark, "namespaced map must be followed by {")
+		return &Bad{Loc: mark, Text: text}
+	}
+	if ns == "" && !auto {
+		r.errorf(mark, "missing namespace in namespaced map")
+	}
+
+	m := r.bare().(*Map)
+	return &NamespacedMap{Mark: mark, Namespace: ns, Auto: auto, Map: m}
+}
+
+func (r *Reader) elems(open token.Span, openText string, closeKind token.Kind) ([]Node, token.Span) {
+	var elems []Node
+	missing := func() token.Span {
+		at := open.End
+		if len(elems) > 0 {
+			at = elems[len(elems)-1].Span().End
+		}
+		r.errorf(token.Span{Start: at, End: at}, "missing %s to close %s at %v", closerText(openText), openText, open.Start)
+		return token.Span{Start: at, End: at}
+	}
+
+	r.open = append(r.open, closeKind)
+	defer func() { r.open = r.open[:len(r.open)-1] }()
+	for {
+		r.skipDiscarded()
+		if r.eof {
+			if r.heuristics {
+				return elems, missing()
+			}
+			r.errorf(open, "unclosed %s", closerText(openText))
+			return elems, token.Span{}
+		}
+
+		if r.isCloser() {
+			if r.tok.Kind() != closeKind {
+				if r.heuristics && slices.Contains(r.open, r.tok.Kind()) {
+					return elems, missing()
+				}
+				if r.heuristics {
+					r.errorf(r.span, "unexpected %s", r.text)
+					r.advance()
+					continue
+				}
+				r.errorf(r.span, "mismatched %s, expected %s", r.text, closerText(openText))
+			} else if r.text != closerText(openText) {
+				r.errorf(r.span, "mismatched %s, expected %s", r.text, closerText(openText))
+			}
+			close := r.span
+			r.advance()
+			return elems, close
+		}
+
+		if r.heuristics && r.lineStart && r.span.Start.Line > open.Start.Line && r.span.Start.Column <= open.Start.Column {
+			return elems, missing()
+		}
+		elems = append(elems, r.forms()...)
+	}
+}
+
+func (r *Reader) balanced() bool {
+	var stack []token.Kind
+	if c := closerOf(r.tok.Kind()); c != token.KindInvalid {
+		stack = append(stack, c)
+	}
+	line := r.span.End.Line
+	for i := 0; ; i++ {
+		if i == len(r.queue) {
+			r.queue = append(r.queue, r.pull())
+		}
+		l := r.queue[i]
+		switch k := l.tok.Kind(); {
+		case l.err != nil:
+			return len(stack) == 0
+		case isTrivia(k):
+			continue
+		case len(stack) == 0 && l.span.Start.Line > line:
+			return true
+		case k == token.KindCloseParen, k == token.KindCloseBracket, k == token.KindCloseBrace:
+			if len(stack) == 0 {
+				return true
+			}
+			if stack[len(stack)-1] != k {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if c := closerOf(k); c != token.KindInvalid {
+				stack = append(stack, c)
+			}
+		}
+		line = l.span.End.Line
+	}
+}
+
+func (r *Reader) skipDiscarded() {
+	for !r.keep && !r.eof && r.tok.Kind() == token.KindDiscard {
+		r.advance()
+		r.form()
+	}
+}
+
+func (r *Reader) advance() {
+	r.spaced = false
+	line := r.span.End.Line
+	for {
+		l := r.next()
+		if l.err != nil {
+			if !errors.Is(l.err, io.EOF) {
+				r.err = l.err
+			}
+			r.eof = true
+			r.tok = token.Token{}
+			r.span = l.span
+			r.text = ""
+			return
+		}
+
+		for _, d := range l.diags {
+			if d.Severity == token.SeverityError {
+				r.errorf(d.Span, "%s", d.Message)
+			}
+		}
+
+		if isTrivia(l.tok.Kind()) {
+			r.spaced = true
+			continue
+		}
+
+		r.tok = l.tok
+		r.span = l.span
+		r.text = l.text
+		r.lineStart = r.span.Start.Line > line
+		return
+	}
+}
+
+func (r *Reader) next() lexeme {
+	if len(r.queue) > 0 {
+		l := r.queue[0]
+		r.queue = r.queue[1:]
+		return l
+	}
+	return r.pull()
+}
+
+func (r *Reader) pull() lexeme {
+	tok, err := r.t.Next()
+	if err != nil {
+		end := r.t.Pos()
+		return lexeme{err: err, span: token.Span{Start: end, End: end}}
+	}
+	diags := r.t.Diagnostics()
+	l := lexeme{tok: tok, span: r.t.Span(), text: r.t.Text(), diags: diags[r.diagnosed:]}
+	r.diagnosed = len(diags)
+	return l
+}
+
+func (r *Reader) isCloser() bool {
+	if r.eof {
+		return false
+	}
+	switch r.tok.Kind() {
+	case token.KindCloseParen, token.KindCloseBracket, token.KindCloseBrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Reader) errorf(span token.Span, format string, args ...any) {
+	r.errs = append(r.errs, &Error{Span: span, Msg: fmt.Sprintf(format, args...)})
+}
+
+func advancePos(p token.Pos, n int) token.Pos {
+	p.Offset += n
+	p.Column += n
+	p.UTF16Column += n
+	return p
+}
+
+func isTrivia(k token.Kind) bool {
+	switch k {
+	case token.KindWhitespace, token.KindLineComment, token.KindBlockComment, token.KindComma:
+		return true
+	default:
+		return false
+	}
+}
+
+func closerOf(k token.Kind) token.Kind {
+	switch k {
+	case token.KindOpenParen, token.KindOpenFn:
+		return token.KindCloseParen
+	case token.KindOpenBracket:
+		return token.KindCloseBracket
+	case token.KindOpenBrace, token.KindOpenSet:
+		return token.KindCloseBrace
+	default:
+		return token.KindInvalid
+	}
+}
+
+func closerText(open string) string {
+	switch open[len(open)-1] {
+	case '(':
+		return ")"
+	case '[':
+		return "]"
+	default:
+		return "}"
+	}
+}
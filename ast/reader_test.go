@@ -0,0 +1,515 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+		errors   []string
+	}{
+		{
+			name:     "empty",
+			source:   "",
+			expected: "",
+		},
+		{
+			name:     "atoms",
+			source:   `foo 123 1.5 "bar" \c`,
+			expected: `foo 123 1.5 "bar" \c`,
+		},
+		{
+			name:     "symbols and keywords",
+			source:   "foo clojure.core/map clojure.core// :a :ns/b ::c ::alias/d",
+			expected: "foo clojure.core/map clojure.core// :a :ns/b ::c ::alias/d",
+		},
+		{
+			name:     "empty keyword",
+			source:   ": ::",
+			expected: ": ::",
+			errors:   []string{"1:1: missing name in keyword", "1:3: missing name in keyword"},
+		},
+		{
+			name:     "collections",
+			source:   "(a [b {c d}] #{e})",
+			expected: "(a [b {c d}] #{e})",
+		},
+		{
+			name:     "comments and whitespace",
+			source:   "; leading\n(a ; inner\n  b)\n",
+			expected: "(a b)",
+		},
+		{
+			name:     "reader macros",
+			source:   "'a `(b ~c ~@d) @e",
+			expected: "(quote a) (syntax-quote (b (unquote c) (unquote-splicing d))) (deref e)",
+		},
+		{
+			name:     "metadata",
+			source:   "^:private ^{:doc \"x\"} foo",
+			expected: `(with-meta (with-meta foo {:doc "x"}) :private)`,
+		},
+		{
+			name:     "tagged literal",
+			source:   `#inst "2020-01-01"`,
+			expected: `(tagged inst "2020-01-01")`,
+		},
+		{
+			name:     "unclosed list",
+			source:   "(a (b c)",
+			expected: "(a (b c))",
+			errors:   []string{"1:1: unclosed )"},
+		},
+		{
+			name:     "unexpected closer",
+			source:   "a) b",
+			expected: "a b",
+			errors:   []string{"1:2: unexpected )"},
+		},
+		{
+			name:     "mismatched closer",
+			source:   "(a [b) c]",
+			expected: "(a [b] c)",
+			errors: []string{
+				"1:6: mismatched ), expected ]",
+				"1:9: mismatched ], expected )",
+			},
+		},
+		{
+			name:     "trailing closers",
+			source:   "a\n)]",
+			expected: "a",
+			errors:   []string{"2:1: unexpected )", "2:2: unexpected ]"},
+		},
+		{
+			name:     "missing form after macro",
+			source:   "(a ')\n'",
+			expected: "(a (quote <bad >)) (quote <bad >)",
+			errors:   []string{"1:5: missing form", "2:2: missing form"},
+		},
+		{
+			name:     "odd map",
+			source:   "{a b c}",
+			expected: "{a b c}",
+			errors:   []string{"1:1: map literal must contain an even number of forms"},
+		},
+		{
+			name:     "invalid token",
+			source:   "(a \x00)",
+			expected: "(a <bad \x00>)",
+			errors:   []string{"1:4: unexpected \"\\x00\""},
+		},
+		{
+			name:     "commas",
+			source:   "{:a 1, :b 2} [,,]",
+			expected: "{:a 1 :b 2} []",
+		},
+		{
+			name:     "dispatch macros",
+			source:   "#(inc %) #'foo #_bar #?(:clj 1) #?@(:clj [2]) ##Inf",
+			expected: "(fn inc %) (var foo) (reader-conditional (:clj 1)) (reader-conditional-splicing (:clj [2])) ##Inf",
+		},
+		{
+			name:     "namespaced maps",
+			source:   "#:foo{:a 1} #::{:b 2} #::bar{:c 3} #:foo {:d 4}",
+			expected: "(namespaced-map foo {:a 1}) (namespaced-map :: {:b 2}) (namespaced-map ::bar {:c 3}) (namespaced-map foo {:d 4})",
+		},
+		{
+			name:     "invalid namespaced maps",
+			source:   "#:foo [] #:{}",
+			expected: "<bad #:foo> [] (namespaced-map  {})",
+			errors: []string{
+				"1:1: namespaced map must be followed by {",
+				"1:10: missing namespace in namespaced map",
+			},
+		},
+		{
+			name:     "regex",
+			source:   `#"a\"b" #"c`,
+			expected: `#"a\"b" #"c`,
+			errors:   []string{"1:9: unterminated regex literal"},
+		},
+		{
+			name:     "unsupported dispatch",
+			source:   "#@a",
+			expected: "<bad #> (deref a)",
+			errors:   []string{"1:1: unknown dispatch macro"},
+		},
+		{
+			name:     "flagged literals",
+			source:   "0x 1e \\",
+			expected: "0x 1e \\",
+			errors: []string{
+				"1:1: missing digits in integer literal",
+				"1:4: missing exponent digits in float literal",
+				"1:7: missing character after backslash",
+			},
+		},
+		{
+			name:     "invalid escape",
+			source:   `"a\qb\xc"`,
+			expected: `"a\qb\xc"`,
+			errors:   []string{"1:3: invalid escape sequence \\q"},
+		},
+		{
+			name:     "numbers",
+			source:   "-5 +3.0 1/3 42N 1.5M 36rZZ 37r1",
+			expected: "-5 +3.0 1/3 42N 1.5M 36rZZ 37r1",
+			errors:   []string{"1:28: radix must be between 2 and 36"},
+		},
+		{
+			name:     "unterminated string",
+			source:   "(a \"b)",
+			expected: "(a \"b))",
+			errors:   []string{"1:4: unterminated string literal", "1:1: unclosed )"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Read(tt.source)
+
+			var received []string
+			var list ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					received = append(received, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			requireEqual(t, fmt.Sprint(tt.errors), fmt.Sprint(received))
+		})
+	}
+}
+
+func TestReaderSpans(t *testing.T) {
+	nodes, err := Read("(a\n  [b c])")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := nodes[0].(*List)
+	requireEqual(t, "1:1-2:9", list.Span().String())
+	requireEqual(t, "2:3-2:8", list.Elems[1].Span().String())
+	requireEqual(t, "2:4-2:5", list.Elems[1].(*Vector).Elems[0].Span().String())
+}
+
+func TestReaderIdentifiers(t *testing.T) {
+	nodes, err := Read("clojure.core/map ::alias/foo #my.ns/tag x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sym := nodes[0].(*Symbol)
+	requireEqual(t, "clojure.core", sym.Namespace)
+	requireEqual(t, "map", sym.Name)
+
+	kw := nodes[1].(*Keyword)
+	requireEqual(t, "alias", kw.Namespace)
+	requireEqual(t, "foo", kw.Name)
+	requireEqual(t, true, kw.AutoResolved)
+
+	tag := nodes[2].(*Tagged).Tag
+	requireEqual(t, "my.ns", tag.Namespace)
+	requireEqual(t, "tag", tag.Name)
+}
+
+func TestReaderTagSpan(t *testing.T) {
+	nodes, err := Read(`#inst "x"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagged := nodes[0].(*Tagged)
+	requireEqual(t, "1:1-1:6", tagged.Mark.String())
+	requireEqual(t, "1:2-1:6", tagged.Tag.Span().String())
+	requireEqual(t, "1:1-1:10", tagged.Span().String())
+}
+
+func TestReaderDiscard(t *testing.T) {
+	tests := []struct {
+		source   string
+		keep     bool
+		expected string
+		errors   []string
+	}{
+		{"#_a b", false, "b", nil},
+		{"#_a b", true, "(discard a) b", nil},
+		{"[1 #_2 3]", false, "[1 3]", nil},
+		{"(a #_b)", false, "(a)", nil},
+		{"#_ #_ a b c", false, "c", nil},
+		{"#_ #_ a b c", true, "(discard (discard a)) b c", nil},
+		{"'#_a b", false, "(quote b)", nil},
+		{"{:a #_:b 1}", false, "{:a 1}", nil},
+		{"#_(a [b) c", false, "", []string{"1:8: mismatched ), expected ]", "1:3: unclosed )"}},
+		{"(a #_)", false, "(a)", []string{"1:6: missing form"}},
+		{"#_a", false, "", nil},
+		{"#_a )", false, "", []string{"1:5: unexpected )"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			var opts []Option
+			if tt.keep {
+				opts = append(opts, KeepDiscarded())
+			}
+			nodes, err := Read(tt.source, opts...)
+
+			var received []string
+			var list ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					received = append(received, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			requireEqual(t, fmt.Sprint(tt.errors), fmt.Sprint(received))
+		})
+	}
+}
+
+func TestReaderRecover(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+		errors   []string
+	}{
+		{
+			name:     "balanced",
+			source:   "(def x\n1)\n(a [b])",
+			expected: "(def x 1) (a [b])",
+		},
+		{
+			name:     "missing closer before top-level form",
+			source:   "(defn f [x]\n  (let [y (inc x)]\n    (* y 2))\n\n(defn g [])",
+			expected: "(defn f [x] (let [y (inc x)] (* y 2))) (defn g [])",
+			errors:   []string{"3:13: missing ) to close ( at 1:1"},
+		},
+		{
+			name:     "missing closer by indentation",
+			source:   "(let [x 1\n  y (inc x)\n(println y)",
+			expected: "(let [x 1] y (inc x)) (println y)",
+			errors:   []string{"1:10: missing ] to close [ at 1:6", "2:12: missing ) to close ( at 1:1"},
+		},
+		{
+			name:     "missing closer at end",
+			source:   "(a (b c)",
+			expected: "(a (b c))",
+			errors:   []string{"1:9: missing ) to close ( at 1:1"},
+		},
+		{
+			name:     "mismatched closer",
+			source:   "(a [b c)\n(d)",
+			expected: "(a [b c]) (d)",
+			errors:   []string{"1:8: missing ] to close [ at 1:4"},
+		},
+		{
+			name:     "extra closer",
+			source:   "(a b]) (c)",
+			expected: "(a b) (c)",
+			errors:   []string{"1:5: unexpected ]"},
+		},
+		{
+			name:     "extra top-level closer",
+			source:   "(a b))\n(c)",
+			expected: "(a b) (c)",
+			errors:   []string{"1:6: unexpected )"},
+		},
+		{
+			name:     "dispatch collections",
+			source:   "#{a #(b\n  :c}",
+			expected: "#{a (fn b) :c}",
+			errors:   []string{"1:8: missing ) to close #( at 1:5"},
+		},
+		{
+			name:     "missing form",
+			source:   "(a ')",
+			expected: "(a (quote <missing>))",
+			errors:   []string{"1:5: missing form"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Read(tt.source, Recover())
+
+			var received []string
+			var list ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					received = append(received, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			requireEqual(t, fmt.Sprint(tt.errors), fmt.Sprint(received))
+		})
+	}
+}
+
+func TestReaderRecoverSpan(t *testing.T) {
+	nodes, _ := Read("(a [b\n(c)", Recover())
+	requireEqual(t, 2, len(nodes))
+	requireEqual(t, "1:1-1:6", nodes[0].Span().String())
+	requireEqual(t, "1:6-1:6", nodes[0].(*List).Elems[1].(*Vector).Close.String())
+}
+
+func TestReaderBlockComment(t *testing.T) {
+	opts := TokenOptions(token.WithDialect(token.SchemeR7RS()))
+
+	nodes, err := Read("#| a |# b #;(c) d", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, "b d", dumpNodes(nodes))
+
+	_, err = Read("#| a #| b |#", opts)
+	requireEqual(t, "1:1: unterminated block comment", fmt.Sprint(err))
+
+	_, err = Read("(a #| b", opts)
+	requireEqual(t, "1:4: unterminated block comment (and 1 more errors)", fmt.Sprint(err))
+}
+
+func TestReaderDialect(t *testing.T) {
+	nodes, err := Read("(let ([x #t]) #| skip |# `(,x))", TokenOptions(token.WithDialect(token.Dialect{
+		Booleans:         true,
+		BlockComments:    true,
+		BracketsAsParens: true,
+		Comma:            token.CommaUnquote,
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, "(let ((x #t)) (syntax-quote ((unquote x))))", dumpNodes(nodes))
+}
+
+func TestReaderDialectMismatchedBrackets(t *testing.T) {
+	_, err := Read("(let ([x 1)] x]", TokenOptions(token.WithDialect(token.Dialect{BracketsAsParens: true})))
+	var list ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("expected errors, received %v", err)
+	}
+	var received []string
+	for _, e := range list {
+		received = append(received, e.Error())
+	}
+	requireEqual(t, "[1:11: mismatched ), expected ] 1:12: mismatched ], expected ) 1:15: mismatched ], expected )]", fmt.Sprint(received))
+}
+
+func TestReaderStream(t *testing.T) {
+	r := NewReader(iotest.OneByteReader(strings.NewReader("(a b) [c]\n  d")))
+
+	var received []string
+	for {
+		node, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, dump(node))
+	}
+
+	requireEqual(t, "[(a b) [c] d]", fmt.Sprint(received))
+}
+
+func TestReaderReadError(t *testing.T) {
+	errBroken := errors.New("broken")
+	r := NewReader(io.MultiReader(strings.NewReader("(a "), iotest.ErrReader(errBroken)))
+	if _, err := r.Read(); !errors.Is(err, errBroken) {
+		t.Fatalf("expected %v, received %v", errBroken, err)
+	}
+}
+
+func dumpNodes(nodes []Node) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = dump(n)
+	}
+	return strings.Join(parts, " ")
+}
+
+func dump(n Node) string {
+	switch n := n.(type) {
+	case *Symbol:
+		return n.String()
+	case *Keyword:
+		return n.String()
+	case *Literal:
+		return n.Text
+	case *List:
+		return "(" + dumpNodes(n.Elems) + ")"
+	case *Vector:
+		return "[" + dumpNodes(n.Elems) + "]"
+	case *Map:
+		return "{" + dumpNodes(n.Elems) + "}"
+	case *Set:
+		return "#{" + dumpNodes(n.Elems) + "}"
+	case *Quote:
+		return "(quote " + dump(n.Form) + ")"
+	case *SyntaxQuote:
+		return "(syntax-quote " + dump(n.Form) + ")"
+	case *Unquote:
+		return "(unquote " + dump(n.Form) + ")"
+	case *UnquoteSplicing:
+		return "(unquote-splicing " + dump(n.Form) + ")"
+	case *Deref:
+		return "(deref " + dump(n.Form) + ")"
+	case *Metadata:
+		return "(with-meta " + dump(n.Form) + " " + dump(n.Meta) + ")"
+	case *Fn:
+		return "(fn " + dumpNodes(n.Elems) + ")"
+	case *NamespacedMap:
+		ns := n.Namespace
+		if n.Auto {
+			ns = "::" + ns
+		}
+		return "(namespaced-map " + ns + " " + dump(n.Map) + ")"
+	case *VarQuote:
+		return "(var " + dump(n.Form) + ")"
+	case *Discard:
+		return "(discard " + dump(n.Form) + ")"
+	case *ReaderConditional:
+		if n.Splicing {
+			return "(reader-conditional-splicing " + dump(n.Form) + ")"
+		}
+		return "(reader-conditional " + dump(n.Form) + ")"
+	case *SymbolicValue:
+		return "##" + n.Name
+	case *Tagged:
+		return "(tagged " + n.Tag.String() + " " + dump(n.Form) + ")"
+	case *Bad:
+		return "<bad " + n.Text + ">"
+	case *Missing:
+		return "<missing>"
+	default:
+		panic(fmt.Errorf("unknown node: %T", n))
+	}
+}
+
+func requireEqual[T comparable](tb testing.TB, expected, received T) {
+	tb.Helper()
+	if expected != received {
+		tb.Fatalf("expected %v, received %v", expected, received)
+	}
+}
@@ -0,0 +1,125 @@
+package ast
+
+func rewrite(n Node, fn func(Node) (Node, error)) (Node, error) {
+	var err error
+	changed := false
+	form := func(f Node) Node {
+		if err != nil {
+			return f
+		}
+		var g Node
+		g, err = rewrite(f, fn)
+		changed = changed || g != f
+		return g
+	}
+	elems := func(elems []Node) []Node {
+		var out []Node
+		for i, e := range elems {
+			g := form(e)
+			if g != e && out == nil {
+				out = make([]Node, len(elems))
+				copy(out, elems[:i])
+			}
+			if out != nil {
+				out[i] = g
+			}
+		}
+		if out == nil {
+			return elems
+		}
+		return out
+	}
+
+	switch n := n.(type) {
+	case *List:
+		if elems := elems(n.Elems); changed {
+			n = &List{Open: n.Open, Close: n.Close, Elems: elems}
+		}
+		return rewriteNode(n, err, fn)
+	case *Vector:
+		if elems := elems(n.Elems); changed {
+			n = &Vector{Open: n.Open, Close: n.Close, Elems: elems}
+		}
+		return rewriteNode(n, err, fn)
+	case *Map:
+		if elems := elems(n.Elems); changed {
+			n = &Map{Open: n.Open, Close: n.Close, Elems: elems}
+		}
+		return rewriteNode(n, err, fn)
+	case *Set:
+		if elems := elems(n.Elems); changed {
+			n = &Set{Open: n.Open, Close: n.Close, Elems: elems}
+		}
+		return rewriteNode(n, err, fn)
+	case *Fn:
+		if elems := elems(n.Elems); changed {
+			n = &Fn{Open: n.Open, Close: n.Close, Elems: elems}
+		}
+		return rewriteNode(n, err, fn)
+	case *NamespacedMap:
+		if m, ok := form(n.Map).(*Map); ok && changed {
+			n = &NamespacedMap{Mark: n.Mark, Namespace: n.Namespace, Auto: n.Auto, Map: m}
+		}
+		return rewriteNode(n, err, fn)
+	case *Quote:
+		if f := form(n.Form); changed {
+			n = &Quote{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *SyntaxQuote:
+		if f := form(n.Form); changed {
+			n = &SyntaxQuote{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *Unquote:
+		if f := form(n.Form); changed {
+			n = &Unquote{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *UnquoteSplicing:
+		if f := form(n.Form); changed {
+			n = &UnquoteSplicing{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *Deref:
+		if f := form(n.Form); changed {
+			n = &Deref{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *VarQuote:
+		if f := form(n.Form); changed {
+			n = &VarQuote{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *Discard:
+		if f := form(n.Form); changed {
+			n = &Discard{Mark: n.Mark, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *ReaderConditional:
+		if f := form(n.Form); changed {
+			n = &ReaderConditional{Mark: n.Mark, Splicing: n.Splicing, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *Metadata:
+		meta, f := form(n.Meta), form(n.Form)
+		if changed {
+			n = &Metadata{Mark: n.Mark, Meta: meta, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	case *Tagged:
+		if f := form(n.Form); changed {
+			n = &Tagged{Mark: n.Mark, Tag: n.Tag, Form: f}
+		}
+		return rewriteNode(n, err, fn)
+	default:
+		return fn(n)
+	}
+}
+
+func rewriteNode(n Node, err error, fn func(Node) (Node, error)) (Node, error) {
+	if err != nil {
+		return n, err
+	}
+	return fn(n)
+}
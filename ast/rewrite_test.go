@@ -0,0 +1,37 @@
+package ast
+
+import (
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	nodes, err := Read("(a [b {c #{d}}] '(e) ^:m f)")
+	requireEqual(t, nil, err)
+	identity := func(n Node) (Node, error) { return n, nil }
+
+	t.Run("unchanged tree is not copied", func(t *testing.T) {
+		node, err := rewrite(nodes[0], identity)
+		requireEqual(t, nil, err)
+		requireEqual(t, nodes[0], node)
+	})
+
+	t.Run("changed path is copied", func(t *testing.T) {
+		node, err := rewrite(nodes[0], func(n Node) (Node, error) {
+			if sym, ok := n.(*Symbol); ok && sym.Name == "c" {
+				return &Symbol{Loc: sym.Loc, Name: "x"}, nil
+			}
+			return n, nil
+		})
+		requireEqual(t, nil, err)
+		requireEqual(t, "(a [b {x #{d}}] (quote (e)) (with-meta f :m))", dump(node))
+		requireEqual(t, "(a [b {c #{d}}] (quote (e)) (with-meta f :m))", dump(nodes[0]))
+
+		before, after := nodes[0].(*List).Elems, node.(*List).Elems
+		requireEqual(t, before[0], after[0])
+		requireEqual(t, before[2], after[2])
+		requireEqual(t, before[3], after[3])
+		requireEqual(t, false, before[1] == after[1])
+		inner := after[1].(*Vector).Elems[1].(*Map).Elems
+		requireEqual(t, before[1].(*Vector).Elems[1].(*Map).Elems[1], inner[1])
+	})
+}
@@ -0,0 +1,226 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Resolver func(namespace, name string) (string, string)
+
+func InNamespace(ns string) Resolver {
+	return func(namespace, name string) (string, string) {
+		if namespace == "" {
+			return ns, name
+		}
+		return namespace, name
+	}
+}
+
+type Expander struct {
+	resolve Resolver
+	gensym  int
+	errs    ErrorList
+}
+
+func NewExpander(r Resolver) *Expander {
+	return &Expander{resolve: r}
+}
+
+func (e *Expander) Expand(n Node) (Node, error) {
+	n, errs := e.expand(n)
+	return n, errs.Err()
+}
+
+func (e *Expander) expand(n Node) (Node, ErrorList) {
+	e.errs = nil
+	n, _ = rewrite(n, func(n Node) (Node, error) {
+		if sq, ok := n.(*SyntaxQuote); ok {
+			q := &quoter{e: e, gensyms: map[string]string{}}
+			return q.quote(sq.Form), nil
+		}
+		return n, nil
+	})
+	return n, e.errs
+}
+
+var specialForms = map[string]bool{
+	"def": true, "loop*": true, "recur": true, "if": true, "case*": true, "let*": true, "letfn*": true,
+	"do": true, "fn*": true, "quote": true, "var": true, "import*": true, ".": true, "set!": true,
+	"deftype*": true, "reify*": true, "try": true, "throw": true, "monitor-enter": true,
+	"monitor-exit": true, "catch": true, "finally": true, "new": true, "&": true,
+}
+
+type quoter struct {
+	e       *Expander
+	gensyms map[string]string
+}
+
+func (q *quoter) quote(n Node) Node {
+	switch n := n.(type) {
+	case *Symbol:
+		if n.Namespace == "" && (n.Name == "nil" || n.Name == "true" || n.Name == "false") {
+			return n
+		}
+		return q.list(n.Loc, q.special(n.Loc, "quote"), q.symbol(n))
+	case *Keyword, *Literal, *SymbolicValue:
+		return n
+	case *Unquote:
+		return n.Form
+	case *UnquoteSplicing:
+		q.errorf(n.Span(), "unquote-splicing not in list")
+		return n
+	case *List:
+		if len(n.Elems) == 0 {
+			return q.list(n.Span(), q.core(n.Span(), "list"))
+		}
+		return q.concat(n.Span(), n.Elems)
+	case *Vector:
+		return q.list(n.Span(), q.core(n.Span(), "apply"), q.core(n.Span(), "vector"), q.concat(n.Span(), n.Elems))
+	case *Map:
+		return q.list(n.Span(), q.core(n.Span(), "apply"), q.core(n.Span(), "hash-map"), q.concat(n.Span(), n.Elems))
+	case *Set:
+		return q.list(n.Span(), q.core(n.Span(), "apply"), q.core(n.Span(), "hash-set"), q.concat(n.Span(), n.Elems))
+	case *Quote:
+		return q.quote(q.list(n.Span(), q.special(n.Mark, "quote"), n.Form))
+	case *Deref:
+		return q.quote(q.list(n.Span(), q.core(n.Mark, "deref"), n.Form))
+	case *VarQuote:
+		return q.quote(q.list(n.Span(), q.special(n.Mark, "var"), n.Form))
+	case *Metadata:
+		meta := withoutLocation(n.Meta)
+		if meta == nil {
+			return q.quote(n.Form)
+		}
+		return q.list(n.Span(), q.core(n.Mark, "with-meta"), q.quote(n.Form), q.quote(meta))
+	case *Fn:
+		return q.quote(q.fn(n))
+	case *Tagged:
+		return &Tagged{Mark: n.Mark, Tag: n.Tag, Form: q.quote(n.Form)}
+	case *NamespacedMap:
+		elems := make([]Node, len(n.Map.Elems))
+		for i, elem := range n.Map.Elems {
+			elems[i] = q.quote(elem)
+		}
+		m := &Map{Open: n.Map.Open, Close: n.Map.Close, Elems: elems}
+		return &NamespacedMap{Mark: n.Mark, Namespace: n.Namespace, Auto: n.Auto, Map: m}
+	default:
+		q.errorf(n.Span(), "unsupported form in syntax-quote")
+		return n
+	}
+}
+
+func (q *quoter) fn(n *Fn) Node {
+	q.e.gensym++
+	id := strconv.Itoa(q.e.gensym)
+	arity, rest := 0, false
+	body, _ := rewrite(&List{Open: n.Open, Close: n.Close, Elems: n.Elems}, func(node Node) (Node, error) {
+		sym, ok := node.(*Symbol)
+		if !ok || sym.Namespace != "" || !strings.HasPrefix(sym.Name, "%") {
+			return node, nil
+		}
+		switch arg := sym.Name[1:]; arg {
+		case "&":
+			rest = true
+			return &Symbol{Loc: sym.Loc, Name: "rest__" + id + "#"}, nil
+		case "":
+			arg = "1"
+			fallthrough
+		default:
+			i, err := strconv.Atoi(arg)
+			if err != nil || i < 1 {
+				return node, nil
+			}
+			arity = max(arity, i)
+			return &Symbol{Loc: sym.Loc, Name: "p" + arg + "__" + id + "#"}, nil
+		}
+	})
+
+	at := token.Span{Start: n.Open.Start, End: n.Open.Start}
+	var params []Node
+	for i := 1; i <= arity; i++ {
+		params = append(params, &Symbol{Loc: at, Name: "p" + strconv.Itoa(i) + "__" + id + "#"})
+	}
+	if rest {
+		params = append(params, &Symbol{Loc: at, Name: "&"}, &Symbol{Loc: at, Name: "rest__" + id + "#"})
+	}
+	vec := &Vector{Open: at, Close: at, Elems: params}
+	return q.list(n.Span(), q.special(n.Open, "fn*"), vec, body)
+}
+
+func (q *quoter) concat(span token.Span, elems []Node) Node {
+	parts := []Node{q.core(span, "concat")}
+	for _, elem := range elems {
+		if s, ok := elem.(*UnquoteSplicing); ok {
+			parts = append(parts, s.Form)
+			continue
+		}
+		parts = append(parts, q.list(elem.Span(), q.core(elem.Span(), "list"), q.quote(elem)))
+	}
+	return q.list(span, q.core(span, "seq"), q.list(span, parts...))
+}
+
+func (q *quoter) symbol(n *Symbol) *Symbol {
+	switch {
+	case n.Namespace != "":
+	case specialForms[n.Name]:
+		return n
+	case strings.HasSuffix(n.Name, "#") && len(n.Name) > 1:
+		name, ok := q.gensyms[n.Name]
+		if !ok {
+			q.e.gensym++
+			name = n.Name[:len(n.Name)-1] + "__" + strconv.Itoa(q.e.gensym) + "__auto__"
+			q.gensyms[n.Name] = name
+		}
+		return &Symbol{Loc: n.Loc, Name: name}
+	case strings.HasPrefix(n.Name, ".") || strings.HasSuffix(n.Name, "."):
+		return n
+	}
+	if q.e.resolve == nil {
+		return n
+	}
+	ns, name := q.e.resolve(n.Namespace, n.Name)
+	return &Symbol{Loc: n.Loc, Namespace: ns, Name: name}
+}
+
+func (q *quoter) list(span token.Span, elems ...Node) *List {
+	return &List{
+		Open:  token.Span{Start: span.Start, End: span.Start},
+		Close: token.Span{Start: span.End, End: span.End},
+		Elems: elems,
+	}
+}
+
+func (q *quoter) special(span token.Span, name string) *Symbol {
+	return &Symbol{Loc: token.Span{Start: span.Start, End: span.Start}, Name: name}
+}
+
+func (q *quoter) core(span token.Span, name string) *Symbol {
+	return &Symbol{Loc: token.Span{Start: span.Start, End: span.Start}, Namespace: "clojure.core", Name: name}
+}
+
+func (q *quoter) errorf(span token.Span, msg string) {
+	q.e.errs = append(q.e.errs, &Error{Span: span, Msg: msg})
+}
+
+func withoutLocation(meta Node) Node {
+	m, ok := meta.(*Map)
+	if !ok {
+		return meta
+	}
+	var elems []Node
+	for i := 0; i+1 < len(m.Elems); i += 2 {
+		if kw, ok := m.Elems[i].(*Keyword); ok && synthesized(kw.Loc) {
+			switch kw.Name {
+			case "line", "column", "file":
+				continue
+			}
+		}
+		elems = append(elems, m.Elems[i], m.Elems[i+1])
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+	return &Map{Open: m.Open, Close: m.Close, Elems: elems}
+}
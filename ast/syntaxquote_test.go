@@ -0,0 +1,166 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandSyntaxQuote(t *testing.T) {
+	const (
+		seq    = "clojure.core/seq"
+		concat = "clojure.core/concat"
+		list   = "clojure.core/list"
+	)
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+		errors   []string
+	}{
+		{
+			name:     "symbol",
+			source:   "`foo",
+			expected: "(quote user/foo)",
+		},
+		{
+			name:     "qualified symbol",
+			source:   "`str/join",
+			expected: "(quote str/join)",
+		},
+		{
+			name:     "special forms and constants",
+			source:   "`if `nil `true `.method `Klass.",
+			expected: "(quote if) nil true (quote .method) (quote Klass.)",
+		},
+		{
+			name:     "atoms",
+			source:   "`:a `1 `\"s\" `##Inf",
+			expected: `:a 1 "s" ##Inf`,
+		},
+		{
+			name:     "unquote",
+			source:   "`~x",
+			expected: "x",
+		},
+		{
+			name:     "empty list",
+			source:   "`()",
+			expected: "(" + list + ")",
+		},
+		{
+			name:     "list",
+			source:   "`(a ~b ~@c)",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote user/a)) (" + list + " b) c))",
+		},
+		{
+			name:     "vector",
+			source:   "`[a]",
+			expected: "(clojure.core/apply clojure.core/vector (" + seq + " (" + concat + " (" + list + " (quote user/a)))))",
+		},
+		{
+			name:     "map",
+			source:   "`{:a ~b}",
+			expected: "(clojure.core/apply clojure.core/hash-map (" + seq + " (" + concat + " (" + list + " :a) (" + list + " b))))",
+		},
+		{
+			name:     "set",
+			source:   "`#{~a}",
+			expected: "(clojure.core/apply clojure.core/hash-set (" + seq + " (" + concat + " (" + list + " a))))",
+		},
+		{
+			name:     "quote",
+			source:   "`'a",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote quote)) (" + list + " (quote user/a))))",
+		},
+		{
+			name:     "deref",
+			source:   "`@a",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote clojure.core/deref)) (" + list + " (quote user/a))))",
+		},
+		{
+			name:     "metadata",
+			source:   "`^:m a",
+			expected: "(clojure.core/with-meta (quote user/a) :m)",
+		},
+		{
+			name:     "gensyms share a scope",
+			source:   "`(x# x# y#)",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote x__1__auto__)) (" + list + " (quote x__1__auto__)) (" + list + " (quote y__2__auto__))))",
+		},
+		{
+			name:     "gensyms are fresh per scope",
+			source:   "`x# `x#",
+			expected: "(quote x__1__auto__) (quote x__2__auto__)",
+		},
+		{
+			name:     "nested",
+			source:   "`(a `b)",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote user/a)) (" + list + " (" + seq + " (" + concat + " (" + list + " (quote quote)) (" + list + " (quote user/b)))))))",
+		},
+		{
+			name:     "outside syntax-quote",
+			source:   "(a ~b)",
+			expected: "(a (unquote b))",
+		},
+		{
+			name:     "top-level splice",
+			source:   "`~@a",
+			expected: "(unquote-splicing a)",
+			errors:   []string{"1:2: unquote-splicing not in list"},
+		},
+		{
+			name:     "fn literal",
+			source:   "`(map #(inc %) xs)",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote user/map)) (" + list + " (" + seq + " (" + concat + " (" + list + " (quote fn*)) (" + list + " (clojure.core/apply clojure.core/vector (" + seq + " (" + concat + " (" + list + " (quote p1__1__2__auto__)))))) (" + list + " (" + seq + " (" + concat + " (" + list + " (quote user/inc)) (" + list + " (quote p1__1__2__auto__)))))))) (" + list + " (quote user/xs))))",
+		},
+		{
+			name:     "fn literal arguments",
+			source:   "`#(%2 %&)",
+			expected: "(" + seq + " (" + concat + " (" + list + " (quote fn*)) (" + list + " (clojure.core/apply clojure.core/vector (" + seq + " (" + concat + " (" + list + " (quote p1__1__2__auto__)) (" + list + " (quote p2__1__3__auto__)) (" + list + " (quote &)) (" + list + " (quote rest__1__4__auto__)))))) (" + list + " (" + seq + " (" + concat + " (" + list + " (quote p2__1__3__auto__)) (" + list + " (quote rest__1__4__auto__)))))))",
+		},
+		{
+			name:     "tagged literal",
+			source:   "`#inst ~x",
+			expected: "(tagged inst x)",
+		},
+		{
+			name:     "namespaced map",
+			source:   "`#:a{:b c}",
+			expected: "(namespaced-map a {:b (quote user/c)})",
+		},
+		{
+			name:     "unsupported",
+			source:   "`#?(:clj a)",
+			expected: "(reader-conditional (:clj a))",
+			errors:   []string{"1:2: unsupported form in syntax-quote"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Read(tt.source, ExpandSyntaxQuote(InNamespace("user")))
+			requireEqual(t, tt.expected, dumpNodes(nodes))
+			var received []string
+			if err != nil {
+				for _, e := range err.(ErrorList) {
+					received = append(received, e.Error())
+				}
+			}
+			requireEqual(t, strings.Join(tt.errors, "\n"), strings.Join(received, "\n"))
+		})
+	}
+}
+
+func TestExpanderWithoutResolver(t *testing.T) {
+	nodes, err := Read("`(a b/c)")
+	requireEqual(t, nil, err)
+	node, err := NewExpander(nil).Expand(nodes[0])
+	requireEqual(t, nil, err)
+	requireEqual(t, "(clojure.core/seq (clojure.core/concat (clojure.core/list (quote a)) (clojure.core/list (quote b/c))))", dump(node))
+}
+
+func TestExpandSyntaxQuoteSpan(t *testing.T) {
+	nodes, err := Read("  `(a b)", ExpandSyntaxQuote(InNamespace("user")))
+	requireEqual(t, nil, err)
+	requireEqual(t, "1:4-1:9", nodes[0].Span().String())
+}
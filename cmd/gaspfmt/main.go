@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jussi-kalliokoski/gasp/format"
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+var dialects = map[string]func() token.Dialect{
+	"clojure":    token.Clojure,
+	"edn":        token.EDN,
+	"scheme":     token.SchemeR7RS,
+	"commonlisp": token.CommonLisp,
+}
+
+func main() {
+	write := flag.Bool("w", false, "write result to source file instead of stdout")
+	list := flag.Bool("l", false, "list files whose formatting differs")
+	dialect := flag.String("dialect", "clojure", "source dialect: clojure, edn, scheme or commonlisp")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gaspfmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	d, ok := dialects[*dialect]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "gaspfmt: unknown dialect %q\n", *dialect)
+		os.Exit(2)
+	}
+	opts := []format.Option{format.TokenOptions(token.WithDialect(d()))}
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "gaspfmt: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		if err := run(os.Stdin, os.Stdout, "<standard input>", *list, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "gaspfmt: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	status := 0
+	for _, path := range flag.Args() {
+		if err := runFile(path, *write, *list, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "gaspfmt: %v\n", err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
+
+func run(r io.Reader, w io.Writer, name string, list bool, opts []format.Option) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	out, err := format.Source(string(src), opts...)
+	if err != nil {
+		return fmt.Errorf("%s:%w", name, err)
+	}
+	if list {
+		if out != string(src) {
+			_, err = fmt.Fprintln(w, name)
+		}
+		return err
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+func runFile(path string, write, list bool, opts []format.Option) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	out, err := format.Source(string(src), opts...)
+	if err != nil {
+		return fmt.Errorf("%s:%w", path, err)
+	}
+	if list && out != string(src) {
+		fmt.Println(path)
+	}
+	if write {
+		if out == string(src) {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(out), info.Mode().Perm())
+	}
+	if !list {
+		_, err = io.WriteString(os.Stdout, out)
+	}
+	return err
+}
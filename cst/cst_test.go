@@ -0,0 +1,218 @@
+package cst
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "empty",
+			source:   "",
+			expected: "(root)",
+		},
+		{
+			name:     "atoms and trivia",
+			source:   "foo ; bar\n 12",
+			expected: `(root "foo" " " "; bar" "\n " "12")`,
+		},
+		{
+			name:     "collections",
+			source:   "(a [b] {c d} #{e})",
+			expected: `(root (list "(" "a" " " (vector "[" "b" "]") " " (map "{" "c" " " "d" "}") " " (set "#{" "e" "}") ")"))`,
+		},
+		{
+			name:     "trivia inside collections",
+			source:   "( a ;x\n )",
+			expected: `(root (list "(" " " "a" " " ";x" "\n " ")"))`,
+		},
+		{
+			name:     "reader macros",
+			source:   "' a `~b ~@c @d",
+			expected: `(root (quote "'" " " "a") " " (syntax-quote "` + "`" + `" (unquote "~" "b")) " " (unquote-splicing "~@" "c") " " (deref "@" "d"))`,
+		},
+		{
+			name:     "metadata",
+			source:   "^:a ^b c",
+			expected: `(root (metadata "^" ":a" " " (metadata "^" "b" " " "c")))`,
+		},
+		{
+			name:     "tagged",
+			source:   `#inst "x"`,
+			expected: `(root (tagged "#inst" " " "\"x\""))`,
+		},
+		{
+			name:     "unclosed",
+			source:   "(a [b",
+			expected: `(root (list "(" "a" " " (vector "[" "b")))`,
+		},
+		{
+			name:     "stray closer",
+			source:   "a ) b",
+			expected: `(root "a" " " (error ")") " " "b")`,
+		},
+		{
+			name:     "incomplete prefix",
+			source:   "(a ')",
+			expected: `(root (list "(" "a" " " (quote "'") ")"))`,
+		},
+		{
+			name:     "dispatch macros",
+			source:   "#(f %) #'a #_ b #?(:c d) #?@(:e f) #::{} ##Inf",
+			expected: `(root (fn "#(" "f" " " "%" ")") " " (var-quote "#'" "a") " " (discard "#_" " " "b") " " (reader-conditional "#?" (list "(" ":c" " " "d" ")")) " " (reader-conditional "#?@" (list "(" ":e" " " "f" ")")) " " (namespaced-map "#::" (map "{" "}")) " " "##Inf")`,
+		},
+		{
+			name:     "discard is trivia for prefixes",
+			source:   "'#_a b ^#_c d e",
+			expected: `(root (quote "'" (discard "#_" "a") " " "b") " " (metadata "^" (discard "#_" "c") " " "d" " " "e"))`,
+		},
+		{
+			name:     "nested discard",
+			source:   "#_ #_ a b c",
+			expected: `(root (discard "#_" " " (discard "#_" " " "a") " " "b") " " "c")`,
+		},
+		{
+			name:     "bare dispatch",
+			source:   "# (a)",
+			expected: `(root (error "#") " " (list "(" "a" ")"))`,
+		},
+		{
+			name:     "invalid token",
+			source:   "(\x00)",
+			expected: `(root (list "(" (error "\x00") ")"))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, _ := Parse(tt.source)
+			requireEqual(t, tt.expected, dump(root))
+			requireEqual(t, tt.source, root.Text())
+		})
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	source := "(a #| b |# [c] ,d)"
+	root, err := Parse(source, token.WithDialect(token.SchemeR7RS()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, `(root (list "(" "a" " " "#| b |#" " " (vector "[" "c" "]") " " (unquote "," "d") ")"))`, dump(root))
+	requireEqual(t, source, root.Text())
+}
+
+func TestParseFailFast(t *testing.T) {
+	root, err := Parse("(a) \x01 (b)", token.FailFast())
+	var diags token.Diagnostics
+	if !errors.As(err, &diags) {
+		t.Fatalf("expected diagnostics, got %v", err)
+	}
+	requireEqual(t, "(a) ", root.Text())
+}
+
+func TestCommaTrivia(t *testing.T) {
+	source := "{:a 1, :b 2}"
+	root, err := Parse(source, token.WithDialect(token.Dialect{Comma: token.CommaToken}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, `(root (map "{" ":a" " " "1" "," " " ":b" " " "2" "}"))`, dump(root))
+
+	m := root.Nodes()[0]
+	var trivia int
+	for _, tok := range m.Tokens() {
+		if tok.IsTrivia() {
+			trivia++
+		}
+	}
+	requireEqual(t, 4, trivia)
+}
+
+func TestDiscardTrivia(t *testing.T) {
+	root, err := Parse("(#_a b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := root.Nodes()[0]
+	requireEqual(t, false, list.IsTrivia())
+	requireEqual(t, true, list.Nodes()[0].IsTrivia())
+	requireEqual(t, "#_a", list.Nodes()[0].Text())
+}
+
+func TestRoundTrip(t *testing.T) {
+	sources := []string{
+		"",
+		"   \n\t ",
+		"(defn foo\r\n  \"doc\" ; comment\n  [x]\n  (+ x 1))\n",
+		"(((",
+		")))",
+		"'''",
+		"^^^",
+		"#",
+		"(a \"unterminated",
+		"[1 0x 1e \\ \x00 ~@ ` ; end",
+		"{:a 1 :b [2 3 #{4}] :c '(5 ^:x 6)}",
+	}
+
+	for _, source := range sources {
+		t.Run(fmt.Sprintf("%q", source), func(t *testing.T) {
+			root, _ := Parse(source)
+			requireEqual(t, source, root.Text())
+			requireEqual(t, len(source), root.Green().Width())
+			requireEqual(t, len(source), root.End())
+		})
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	root, err := Parse("(a [b c])")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok := root.TokenAt(4)
+	requireEqual(t, "b", tok.Text())
+	requireEqual(t, 4, tok.Offset())
+	requireEqual(t, KindVector, tok.Parent().Kind())
+	requireEqual(t, 3, tok.Parent().Offset())
+	requireEqual(t, KindList, tok.Parent().Parent().Kind())
+	requireEqual(t, KindRoot, tok.Parent().Parent().Parent().Kind())
+
+	requireEqual(t, (*Token)(nil), root.TokenAt(9))
+	requireEqual(t, ")", root.TokenAt(8).Text())
+
+	list := root.Nodes()[0]
+	requireEqual(t, 4, len(list.Tokens()))
+	requireEqual(t, 1, len(list.Nodes()))
+	requireEqual(t, "[b c]", list.Nodes()[0].Text())
+}
+
+func dump(n *Node) string {
+	parts := []string{n.Kind().String()}
+	for _, c := range n.Children() {
+		switch c := c.(type) {
+		case *Node:
+			parts = append(parts, dump(c))
+		case *Token:
+			parts = append(parts, fmt.Sprintf("%q", c.Text()))
+		}
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func requireEqual[T comparable](tb testing.TB, expected, received T) {
+	tb.Helper()
+	if expected != received {
+		tb.Fatalf("expected %v, received %v", expected, received)
+	}
+}
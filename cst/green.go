@@ -0,0 +1,160 @@
+package cst
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Kind uint8
+
+const (
+	KindRoot Kind = iota
+	KindList
+	KindVector
+	KindMap
+	KindSet
+	KindFn
+	KindNamespacedMap
+	KindQuote
+	KindSyntaxQuote
+	KindUnquote
+	KindUnquoteSplicing
+	KindDeref
+	KindVarQuote
+	KindDiscard
+	KindReaderConditional
+	KindMetadata
+	KindTagged
+	KindError
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindRoot:
+		return "root"
+	case KindList:
+		return "list"
+	case KindVector:
+		return "vector"
+	case KindMap:
+		return "map"
+	case KindSet:
+		return "set"
+	case KindFn:
+		return "fn"
+	case KindNamespacedMap:
+		return "namespaced-map"
+	case KindQuote:
+		return "quote"
+	case KindSyntaxQuote:
+		return "syntax-quote"
+	case KindUnquote:
+		return "unquote"
+	case KindUnquoteSplicing:
+		return "unquote-splicing"
+	case KindDeref:
+		return "deref"
+	case KindVarQuote:
+		return "var-quote"
+	case KindDiscard:
+		return "discard"
+	case KindReaderConditional:
+		return "reader-conditional"
+	case KindMetadata:
+		return "metadata"
+	case KindTagged:
+		return "tagged"
+	case KindError:
+		return "error"
+	default:
+		panic(fmt.Errorf("unknown kind: %d", k))
+	}
+}
+
+type GreenElement interface {
+	Width() int
+	writeTo(*strings.Builder)
+}
+
+type GreenNode struct {
+	kind     Kind
+	width    int
+	children []GreenElement
+}
+
+func NewGreenNode(kind Kind, children ...GreenElement) *GreenNode {
+	width := 0
+	for _, c := range children {
+		width += c.Width()
+	}
+	return &GreenNode{kind: kind, width: width, children: children}
+}
+
+func (n *GreenNode) Kind() Kind {
+	return n.kind
+}
+
+func (n *GreenNode) Width() int {
+	return n.width
+}
+
+func (n *GreenNode) Children() []GreenElement {
+	return n.children
+}
+
+func (n *GreenNode) Text() string {
+	var b strings.Builder
+	b.Grow(n.width)
+	n.writeTo(&b)
+	return b.String()
+}
+
+func (n *GreenNode) IsTrivia() bool {
+	return n.kind == KindDiscard
+}
+
+func (n *GreenNode) writeTo(b *strings.Builder) {
+	for _, c := range n.children {
+		c.writeTo(b)
+	}
+}
+
+type GreenToken struct {
+	token token.Token
+	text  string
+}
+
+func NewGreenToken(tok token.Token, text string) *GreenToken {
+	return &GreenToken{token: tok, text: text}
+}
+
+func (t *GreenToken) Token() token.Token {
+	return t.token
+}
+
+func (t *GreenToken) Kind() token.Kind {
+	return t.token.Kind()
+}
+
+func (t *GreenToken) Width() int {
+	return len(t.text)
+}
+
+func (t *GreenToken) Text() string {
+	return t.text
+}
+
+func (t *GreenToken) IsTrivia() bool {
+	switch t.token.Kind() {
+	case token.KindWhitespace, token.KindLineComment, token.KindBlockComment, token.KindComma:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *GreenToken) writeTo(b *strings.Builder) {
+	b.WriteString(t.text)
+}
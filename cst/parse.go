@@ -0,0 +1,148 @@
+package cst
+
+import (
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func Parse(src string, opts ...token.Option) (*Node, error) {
+	var sc sliceConsumer
+	err := token.Tokenize(&sc, src, opts...)
+	return NewRoot(Build(src, sc.tokens)), err
+}
+
+func Build(src string, tokens []token.Token) *GreenNode {
+	greens := make([]*GreenToken, len(tokens))
+	offset := 0
+	for i, tok := range tokens {
+		greens[i] = NewGreenToken(tok, src[offset:offset+tok.Len()])
+		offset += tok.Len()
+	}
+
+	b := &builder{}
+	b.push(KindRoot, false, 0)
+	for _, tok := range greens {
+		switch tok.Kind() {
+		case token.KindWhitespace, token.KindLineComment, token.KindBlockComment, token.KindComma:
+			b.add(tok)
+		case token.KindSymbol, token.KindKeyword, token.KindLiteral:
+			b.form(tok)
+		case token.KindOpenParen:
+			b.push(KindList, false, 0, tok)
+		case token.KindOpenBracket:
+			b.push(KindVector, false, 0, tok)
+		case token.KindOpenBrace:
+			b.push(KindMap, false, 0, tok)
+		case token.KindCloseParen, token.KindCloseBracket, token.KindCloseBrace:
+			b.close(tok)
+		case token.KindQuote:
+			b.push(KindQuote, true, 1, tok)
+		case token.KindBackquote:
+			b.push(KindSyntaxQuote, true, 1, tok)
+		case token.KindUnquote:
+			b.push(KindUnquote, true, 1, tok)
+		case token.KindUnquoteSplicing:
+			b.push(KindUnquoteSplicing, true, 1, tok)
+		case token.KindDeref:
+			b.push(KindDeref, true, 1, tok)
+		case token.KindMetadata:
+			b.push(KindMetadata, true, 2, tok)
+		case token.KindOpenSet:
+			b.push(KindSet, false, 0, tok)
+		case token.KindOpenFn:
+			b.push(KindFn, false, 0, tok)
+		case token.KindVarQuote:
+			b.push(KindVarQuote, true, 1, tok)
+		case token.KindDiscard:
+			b.push(KindDiscard, true, 1, tok)
+		case token.KindReaderConditional, token.KindReaderConditionalSplicing:
+			b.push(KindReaderConditional, true, 1, tok)
+		case token.KindNamespacedMap:
+			b.push(KindNamespacedMap, true, 1, tok)
+		case token.KindTag:
+			b.push(KindTagged, true, 1, tok)
+		case token.KindSymbolicValue:
+			b.form(tok)
+		default:
+			b.form(NewGreenNode(KindError, tok))
+		}
+	}
+
+	for len(b.stack) > 1 {
+		b.popInto()
+	}
+	return b.pop()
+}
+
+type frame struct {
+	kind     Kind
+	prefix   bool
+	need     int
+	children []GreenElement
+}
+
+type builder struct {
+	stack []*frame
+}
+
+func (b *builder) push(kind Kind, prefix bool, need int, children ...GreenElement) {
+	b.stack = append(b.stack, &frame{kind: kind, prefix: prefix, need: need, children: children})
+}
+
+func (b *builder) pop() *GreenNode {
+	f := b.top()
+	b.stack = b.stack[:len(b.stack)-1]
+	return NewGreenNode(f.kind, f.children...)
+}
+
+func (b *builder) popInto() {
+	n := b.pop()
+	b.add(n)
+}
+
+func (b *builder) top() *frame {
+	return b.stack[len(b.stack)-1]
+}
+
+func (b *builder) add(e GreenElement) {
+	top := b.top()
+	top.children = append(top.children, e)
+}
+
+func (b *builder) form(e GreenElement) {
+	for {
+		top := b.top()
+		top.children = append(top.children, e)
+		if !top.prefix {
+			return
+		}
+		top.need--
+		if top.need > 0 {
+			return
+		}
+		e = b.pop()
+		if top.kind == KindDiscard {
+			b.add(e)
+			return
+		}
+	}
+}
+
+func (b *builder) close(tok *GreenToken) {
+	for len(b.stack) > 1 && b.top().prefix {
+		b.popInto()
+	}
+	if len(b.stack) == 1 {
+		b.form(NewGreenNode(KindError, tok))
+		return
+	}
+	b.add(tok)
+	b.form(b.pop())
+}
+
+type sliceConsumer struct {
+	tokens []token.Token
+}
+
+func (sc *sliceConsumer) ConsumeToken(t token.Token) {
+	sc.tokens = append(sc.tokens, t)
+}
@@ -0,0 +1,141 @@
+package cst
+
+import (
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Element interface {
+	Offset() int
+	End() int
+	Text() string
+	Parent() *Node
+}
+
+type Node struct {
+	green  *GreenNode
+	parent *Node
+	offset int
+}
+
+func NewRoot(green *GreenNode) *Node {
+	return &Node{green: green}
+}
+
+func (n *Node) Green() *GreenNode {
+	return n.green
+}
+
+func (n *Node) Kind() Kind {
+	return n.green.kind
+}
+
+func (n *Node) IsTrivia() bool {
+	return n.green.IsTrivia()
+}
+
+func (n *Node) Offset() int {
+	return n.offset
+}
+
+func (n *Node) End() int {
+	return n.offset + n.green.width
+}
+
+func (n *Node) Text() string {
+	return n.green.Text()
+}
+
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+func (n *Node) Children() []Element {
+	children := make([]Element, len(n.green.children))
+	offset := n.offset
+	for i, c := range n.green.children {
+		switch c := c.(type) {
+		case *GreenNode:
+			children[i] = &Node{green: c, parent: n, offset: offset}
+		case *GreenToken:
+			children[i] = &Token{green: c, parent: n, offset: offset}
+		}
+		offset += c.Width()
+	}
+	return children
+}
+
+func (n *Node) Nodes() []*Node {
+	var nodes []*Node
+	for _, c := range n.Children() {
+		if c, ok := c.(*Node); ok {
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+func (n *Node) Tokens() []*Token {
+	var tokens []*Token
+	for _, c := range n.Children() {
+		if c, ok := c.(*Token); ok {
+			tokens = append(tokens, c)
+		}
+	}
+	return tokens
+}
+
+func (n *Node) TokenAt(offset int) *Token {
+	if offset < n.offset || offset >= n.End() {
+		return nil
+	}
+	for _, c := range n.Children() {
+		if offset >= c.End() {
+			continue
+		}
+		switch c := c.(type) {
+		case *Token:
+			return c
+		case *Node:
+			return c.TokenAt(offset)
+		}
+	}
+	return nil
+}
+
+type Token struct {
+	green  *GreenToken
+	parent *Node
+	offset int
+}
+
+func (t *Token) Green() *GreenToken {
+	return t.green
+}
+
+func (t *Token) Kind() token.Kind {
+	return t.green.Kind()
+}
+
+func (t *Token) Token() token.Token {
+	return t.green.token
+}
+
+func (t *Token) IsTrivia() bool {
+	return t.green.IsTrivia()
+}
+
+func (t *Token) Offset() int {
+	return t.offset
+}
+
+func (t *Token) End() int {
+	return t.offset + t.green.Width()
+}
+
+func (t *Token) Text() string {
+	return t.green.text
+}
+
+func (t *Token) Parent() *Node {
+	return t.parent
+}
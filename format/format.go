@@ -0,0 +1,224 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Error struct {
+	Span token.Span
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %s", e.Span.Start, e.Msg)
+}
+
+func Source(src string, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	var buf token.TokenBuffer
+	if err := buf.Tokenize(src, o.token...); err != nil {
+		return "", err
+	}
+
+	f := &formatter{rules: o.rules, stack: []frame{{kind: token.KindInvalid}}}
+	cursor := token.NewCursor()
+	for i := range buf.Len() {
+		text := src[buf.Start(i):buf.End(i)]
+		span := cursor.Advance(text)
+		if err := f.token(buf.At(i), text, span); err != nil {
+			return "", err
+		}
+	}
+	if len(f.stack) > 1 {
+		open := f.stack[len(f.stack)-1]
+		return "", &Error{Span: open.span, Msg: "unclosed delimiter"}
+	}
+	if f.b.Len() > 0 {
+		f.write("\n")
+	}
+	return f.b.String(), nil
+}
+
+type frame struct {
+	kind    token.Kind
+	close   string
+	span    token.Span
+	col     int
+	width   int
+	line    int
+	elems   int
+	pending int
+	head    string
+	argCol  int
+}
+
+type formatter struct {
+	rules  map[string]Rule
+	b      strings.Builder
+	line   int
+	col    int
+	space  string
+	opened bool
+	stack  []frame
+}
+
+func (f *formatter) token(t token.Token, text string, span token.Span) error {
+	if t.Kind() == token.KindWhitespace {
+		f.space += text
+		return nil
+	}
+
+	f.whitespace(t.Kind())
+	top := &f.stack[len(f.stack)-1]
+	switch t.Kind() {
+	case token.KindCloseParen, token.KindCloseBracket, token.KindCloseBrace:
+		if len(f.stack) == 1 {
+			return &Error{Span: span, Msg: fmt.Sprintf("unexpected %s", text)}
+		}
+		if text != top.close {
+			return &Error{Span: span, Msg: fmt.Sprintf("mismatched %s, expected %s", text, top.close)}
+		}
+		f.stack = f.stack[:len(f.stack)-1]
+		f.write(text)
+		f.opened = false
+		return nil
+	case token.KindLineComment:
+		f.write(strings.TrimRight(text, " \t\r"))
+		f.opened = false
+		return nil
+	case token.KindBlockComment, token.KindComma:
+		f.write(text)
+		f.opened = false
+		return nil
+	}
+
+	f.element(top, t, text)
+	col, line := f.col, f.line
+	f.write(text)
+	f.opened = false
+	switch t.Kind() {
+	case token.KindOpenParen, token.KindOpenBracket, token.KindOpenBrace, token.KindOpenSet, token.KindOpenFn:
+		f.stack = append(f.stack, frame{
+			kind:   t.Kind(),
+			close:  closerText(text),
+			span:   span,
+			col:    col,
+			width:  utf8.RuneCountInString(text),
+			line:   line,
+			argCol: -1,
+		})
+		f.opened = true
+	case token.KindMetadata:
+		top.pending += 2
+	case token.KindQuote, token.KindBackquote, token.KindUnquote, token.KindUnquoteSplicing, token.KindDeref,
+		token.KindVarQuote, token.KindDiscard, token.KindReaderConditional, token.KindReaderConditionalSplicing,
+		token.KindNamespacedMap, token.KindTag:
+		top.pending++
+	}
+	return nil
+}
+
+func (f *formatter) element(fr *frame, t token.Token, text string) {
+	if fr.pending > 0 {
+		fr.pending--
+		return
+	}
+	fr.elems++
+	switch {
+	case fr.elems == 1 && t.Kind() == token.KindSymbol:
+		fr.head = t.Symbol().Name(text)
+	case fr.elems == 2 && f.line == fr.line:
+		fr.argCol = f.col
+	}
+}
+
+func (f *formatter) whitespace(next token.Kind) {
+	lines := splitLines(f.space)
+	f.space = ""
+	if f.b.Len() == 0 {
+		return
+	}
+
+	commas := strings.Repeat(",", strings.Count(lines[0], ","))
+	if len(lines) == 1 {
+		if lines[0] != "" && !f.opened && !isCloser(next) {
+			commas += " "
+		}
+		f.write(commas)
+		return
+	}
+
+	f.write(commas + strings.Repeat("\n", len(lines)-1) + strings.Repeat(" ", f.indent()))
+	if n := strings.Count(lines[len(lines)-1], ","); n > 0 {
+		f.write(strings.Repeat(",", n) + " ")
+	}
+}
+
+func (f *formatter) indent() int {
+	fr := &f.stack[len(f.stack)-1]
+	switch fr.kind {
+	case token.KindInvalid:
+		return 0
+	case token.KindOpenParen, token.KindOpenFn:
+		base := fr.col + fr.width - 1
+		if rule, ok := f.rules[fr.head]; ok && (rule.inner || fr.elems > rule.n) {
+			return base + 2
+		}
+		if fr.argCol >= 0 {
+			return fr.argCol
+		}
+		return base + 1
+	default:
+		return fr.col + fr.width
+	}
+}
+
+func (f *formatter) write(s string) {
+	f.b.WriteString(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		f.line += strings.Count(s, "\n")
+		f.col = utf8.RuneCountInString(s[i+1:])
+	} else {
+		f.col += utf8.RuneCountInString(s)
+	}
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for {
+		i := strings.IndexAny(s, "\r\n\u2028\u2029")
+		if i < 0 {
+			return append(lines, s)
+		}
+		lines = append(lines, s[:i])
+		_, size := utf8.DecodeRuneInString(s[i:])
+		if strings.HasPrefix(s[i:], "\r\n") {
+			size = 2
+		}
+		s = s[i+size:]
+	}
+}
+
+func closerText(open string) string {
+	switch open[len(open)-1] {
+	case '(':
+		return ")"
+	case '[':
+		return "]"
+	default:
+		return "}"
+	}
+}
+
+func isCloser(k token.Kind) bool {
+	switch k {
+	case token.KindCloseParen, token.KindCloseBracket, token.KindCloseBrace:
+		return true
+	default:
+		return false
+	}
+}
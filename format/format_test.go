@@ -0,0 +1,211 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "empty",
+			source:   "",
+			expected: "",
+		},
+		{
+			name:     "spacing",
+			source:   "  ( foo   bar\t[ 1  2 ]  )  ",
+			expected: "(foo bar [1 2])\n",
+		},
+		{
+			name:     "argument alignment",
+			source:   "(foo bar\nbaz\n      qux)",
+			expected: "(foo bar\n     baz\n     qux)\n",
+		},
+		{
+			name:     "no arguments on first line",
+			source:   "(foo\nbar\n  baz)",
+			expected: "(foo\n bar\n baz)\n",
+		},
+		{
+			name:     "data lists",
+			source:   "(1 2\n3)",
+			expected: "(1 2\n   3)\n",
+		},
+		{
+			name:     "collections",
+			source:   "[a\nb]\n{:a 1\n:b 2}\n#{x\ny}",
+			expected: "[a\n b]\n{:a 1\n :b 2}\n#{x\n  y}\n",
+		},
+		{
+			name:     "inner rule",
+			source:   "(defn f\n[x]\n(inc x))",
+			expected: "(defn f\n  [x]\n  (inc x))\n",
+		},
+		{
+			name:     "block rule",
+			source:   "(let [x 1\ny 2]\nx)\n(let\n[x 1]\nx)",
+			expected: "(let [x 1\n      y 2]\n  x)\n(let\n [x 1]\n  x)\n",
+		},
+		{
+			name:     "namespaced head",
+			source:   "(clojure.core/when x\ny)",
+			expected: "(clojure.core/when x\n  y)\n",
+		},
+		{
+			name:     "custom rules",
+			source:   "(my-macro a\nb)\n(when a\nb)",
+			opts:     []Option{Rules(map[string]Rule{"my-macro": Inner(), "when": Block(2)})},
+			expected: "(my-macro a\n  b)\n(when a\n      b)\n",
+		},
+		{
+			name:     "comments and blank lines",
+			source:   ";; header   \n\n\n(foo   ; trailing  \n  bar)\n\n  ; own line\n(baz)",
+			expected: ";; header\n\n\n(foo ; trailing\n bar)\n\n; own line\n(baz)\n",
+		},
+		{
+			name:     "reader macros",
+			source:   "(foo ^:private #_x 'bar\n@baz)\n#(inc\n%)",
+			expected: "(foo ^:private #_x 'bar\n     @baz)\n#(inc\n  %)\n",
+		},
+		{
+			name:     "multiline strings",
+			source:   "(foo \"a\n   b\" c\n d)",
+			expected: "(foo \"a\n   b\" c\n     d)\n",
+		},
+		{
+			name:     "commas",
+			source:   "{:a 1 , :b 2,\n:c 3}",
+			expected: "{:a 1, :b 2,\n :c 3}\n",
+		},
+		{
+			name:     "closers on own line",
+			source:   "(foo\n  bar\n        )",
+			expected: "(foo\n bar\n )\n",
+		},
+		{
+			name:     "crlf",
+			source:   "(foo bar\r\nbaz)\r\n",
+			expected: "(foo bar\n     baz)\n",
+		},
+		{
+			name:     "block comments",
+			source:   "(foo #| a\n b |#   bar)",
+			opts:     []Option{TokenOptions(token.WithDialect(token.SchemeR7RS()))},
+			expected: "(foo #| a\n b |# bar)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received, err := Source(tt.source, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, received)
+
+			again, err := Source(received, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, received, again)
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "unexpected closer",
+			source:   "(a))",
+			expected: "1:4: unexpected )",
+		},
+		{
+			name:     "unclosed",
+			source:   "(a\n[b]",
+			expected: "1:1: unclosed delimiter",
+		},
+		{
+			name:     "mismatched closer",
+			source:   "(a\n[b)",
+			expected: "2:3: mismatched ), expected ]",
+		},
+		{
+			name:     "mismatched brackets as parens",
+			source:   "(let ([x 1)] x]",
+			opts:     []Option{TokenOptions(token.WithDialect(token.Dialect{BracketsAsParens: true}))},
+			expected: "1:11: mismatched ), expected ]",
+		},
+		{
+			name:     "tokenizer error",
+			source:   `(a "b`,
+			expected: "1:4: unterminated string literal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Source(tt.source, tt.opts...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			requireEqual(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestSourceCorpus(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "token", "testdata", "corpus", "*.clj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			once, err := Source(string(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			twice, err := Source(once)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, once, twice)
+		})
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	rules := DefaultRules()
+	rules["defn"] = Block(0)
+	requireEqual(t, Inner(), DefaultRules()["defn"])
+
+	received, err := Source("(defn f [x]\nx)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, "(defn f [x]\n  x)\n", received)
+}
+
+func requireEqual[T comparable](tb testing.TB, expected, received T) {
+	tb.Helper()
+	if expected != received {
+		tb.Fatalf("expected %v, received %v", expected, received)
+	}
+}
@@ -0,0 +1,34 @@
+package format
+
+import (
+	"maps"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Option func(*options)
+
+type options struct {
+	token []token.Option
+	rules map[string]Rule
+}
+
+func TokenOptions(opts ...token.Option) Option {
+	return func(o *options) {
+		o.token = append(o.token, opts...)
+	}
+}
+
+func Rules(rules map[string]Rule) Option {
+	return func(o *options) {
+		maps.Copy(o.rules, rules)
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{rules: DefaultRules()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,70 @@
+package format
+
+type Rule struct {
+	inner bool
+	n     int
+}
+
+func Block(n int) Rule {
+	return Rule{n: n}
+}
+
+func Inner() Rule {
+	return Rule{inner: true}
+}
+
+func DefaultRules() map[string]Rule {
+	return map[string]Rule{
+		"as->":            Block(2),
+		"binding":         Block(1),
+		"bound-fn":        Inner(),
+		"case":            Block(1),
+		"catch":           Block(2),
+		"comment":         Block(0),
+		"cond":            Block(0),
+		"cond->":          Block(1),
+		"cond->>":         Block(1),
+		"condp":           Block(2),
+		"def":             Inner(),
+		"defmacro":        Inner(),
+		"defmethod":       Inner(),
+		"defmulti":        Inner(),
+		"defn":            Inner(),
+		"defn-":           Inner(),
+		"defprotocol":     Block(1),
+		"defrecord":       Block(2),
+		"deftype":         Block(2),
+		"delay":           Block(0),
+		"do":              Block(0),
+		"doseq":           Block(1),
+		"dotimes":         Block(1),
+		"doto":            Block(1),
+		"extend":          Block(1),
+		"extend-protocol": Block(1),
+		"extend-type":     Block(1),
+		"finally":         Block(0),
+		"fn":              Inner(),
+		"for":             Block(1),
+		"future":          Block(0),
+		"if":              Block(1),
+		"if-let":          Block(1),
+		"if-not":          Block(1),
+		"if-some":         Block(1),
+		"let":             Block(1),
+		"letfn":           Block(1),
+		"locking":         Block(1),
+		"loop":            Block(1),
+		"ns":              Block(1),
+		"proxy":           Block(2),
+		"reify":           Inner(),
+		"try":             Block(0),
+		"when":            Block(1),
+		"when-first":      Block(1),
+		"when-let":        Block(1),
+		"when-not":        Block(1),
+		"when-some":       Block(1),
+		"while":           Block(1),
+		"with-open":       Block(1),
+		"with-redefs":     Block(1),
+	}
+}
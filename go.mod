@@ -1,3 +1,5 @@
 module github.com/jussi-kalliokoski/gasp
 
-go 1.22
+go 1.23.0
+
+require golang.org/x/text v0.26.0
@@ -0,0 +1,134 @@
+package highlight
+
+import (
+	"fmt"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Class uint8
+
+const (
+	ClassNone Class = iota
+	ClassDelimiter
+	ClassReaderMacro
+	ClassSymbol
+	ClassKeyword
+	ClassConstant
+	ClassNumber
+	ClassString
+	ClassCharacter
+	ClassRegex
+	ClassTag
+	ClassComment
+	ClassInvalid
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassNone:
+		return "none"
+	case ClassDelimiter:
+		return "delimiter"
+	case ClassReaderMacro:
+		return "reader-macro"
+	case ClassSymbol:
+		return "symbol"
+	case ClassKeyword:
+		return "keyword"
+	case ClassConstant:
+		return "constant"
+	case ClassNumber:
+		return "number"
+	case ClassString:
+		return "string"
+	case ClassCharacter:
+		return "character"
+	case ClassRegex:
+		return "regex"
+	case ClassTag:
+		return "tag"
+	case ClassComment:
+		return "comment"
+	case ClassInvalid:
+		return "invalid"
+	default:
+		panic(fmt.Errorf("unknown class: %d", c))
+	}
+}
+
+func Classify(t token.Token, text string) Class {
+	switch t.Kind() {
+	case token.KindWhitespace, token.KindComma:
+		return ClassNone
+	case token.KindOpenParen, token.KindCloseParen, token.KindOpenBracket, token.KindCloseBracket,
+		token.KindOpenBrace, token.KindCloseBrace, token.KindOpenSet, token.KindOpenFn:
+		return ClassDelimiter
+	case token.KindQuote, token.KindBackquote, token.KindDeref, token.KindMetadata, token.KindUnquote,
+		token.KindUnquoteSplicing, token.KindVarQuote, token.KindDiscard, token.KindReaderConditional,
+		token.KindReaderConditionalSplicing, token.KindNamespacedMap:
+		return ClassReaderMacro
+	case token.KindSymbol:
+		switch text {
+		case "nil", "true", "false":
+			return ClassConstant
+		}
+		return ClassSymbol
+	case token.KindKeyword:
+		if t.Keyword().NameOffset() == len(text) {
+			return ClassInvalid
+		}
+		return ClassKeyword
+	case token.KindSymbolicValue:
+		return ClassConstant
+	case token.KindTag:
+		return ClassTag
+	case token.KindLineComment:
+		return ClassComment
+	case token.KindBlockComment:
+		if t.BlockComment().Unterminated() {
+			return ClassInvalid
+		}
+		return ClassComment
+	case token.KindLiteral:
+		return classifyLiteral(t.Literal())
+	default:
+		return ClassInvalid
+	}
+}
+
+func classifyLiteral(l token.Literal) Class {
+	switch l.Kind() {
+	case token.LiteralKindInteger:
+		if l.Integer().EmptyInt() || l.Integer().InvalidRadix() {
+			return ClassInvalid
+		}
+		return ClassNumber
+	case token.LiteralKindFloat:
+		if l.Float().EmptyExponent() {
+			return ClassInvalid
+		}
+		return ClassNumber
+	case token.LiteralKindRatio:
+		return ClassNumber
+	case token.LiteralKindString:
+		if l.String().Unterminated() || l.String().InvalidEscape() {
+			return ClassInvalid
+		}
+		return ClassString
+	case token.LiteralKindCharacter:
+		if l.Character().MissingCharacter() {
+			return ClassInvalid
+		}
+		return ClassCharacter
+	case token.LiteralKindRegex:
+		if l.Regex().Unterminated() {
+			return ClassInvalid
+		}
+		return ClassRegex
+	case token.LiteralKindBoolean:
+		return ClassConstant
+	default:
+		panic(fmt.Errorf("unknown literal kind: %d", l.Kind()))
+	}
+}
@@ -0,0 +1,61 @@
+package highlight
+
+import (
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Segment struct {
+	Start int
+	End   int
+	Class Class
+}
+
+func Segments(src string, opts ...Option) []Segment {
+	o := newOptions(opts)
+	var buf token.TokenBuffer
+	_ = buf.Tokenize(src, o.token...)
+	segments := make([]Segment, buf.Len())
+	for i := range segments {
+		start, end := buf.Start(i), buf.End(i)
+		segments[i] = Segment{Start: start, End: end, Class: Classify(buf.At(i), src[start:end])}
+	}
+	return segments
+}
+
+func ANSI(w io.Writer, src string, opts ...Option) error {
+	o := newOptions(opts)
+	for _, s := range Segments(src, opts...) {
+		text := src[s.Start:s.End]
+		if esc := o.theme[s.Class].ansi(); esc != "" {
+			text = esc + text + "\x1b[0m"
+		}
+		if _, err := io.WriteString(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func HTML(w io.Writer, src string, opts ...Option) error {
+	o := newOptions(opts)
+	for _, s := range Segments(src, opts...) {
+		text := html.EscapeString(src[s.Start:s.End])
+		switch {
+		case s.Class == ClassNone:
+		case o.inlineStyles:
+			if css := o.theme[s.Class].css(); css != "" {
+				text = fmt.Sprintf(`<span style="%s">%s</span>`, css, text)
+			}
+		default:
+			text = fmt.Sprintf(`<span class="%s%s">%s</span>`, o.classPrefix, s.Class, text)
+		}
+		if _, err := io.WriteString(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
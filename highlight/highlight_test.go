@@ -0,0 +1,142 @@
+package highlight
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "collections",
+			source:   "(a [b] #{c} #(d))",
+			expected: `delimiter:"(" symbol:"a" none:" " delimiter:"[" symbol:"b" delimiter:"]" none:" " delimiter:"#{" symbol:"c" delimiter:"}" none:" " delimiter:"#(" symbol:"d" delimiter:")" delimiter:")"`,
+		},
+		{
+			name:     "symbols and keywords",
+			source:   "foo/bar :baz ::qux nil true false",
+			expected: `symbol:"foo/bar" none:" " keyword:":baz" none:" " keyword:"::qux" none:" " constant:"nil" none:" " constant:"true" none:" " constant:"false"`,
+		},
+		{
+			name:     "literals",
+			source:   `1 2.5 1/2 "s" \c #"r" ##Inf`,
+			expected: `number:"1" none:" " number:"2.5" none:" " number:"1/2" none:" " string:"\"s\"" none:" " character:"\\c" none:" " regex:"#\"r\"" none:" " constant:"##Inf"`,
+		},
+		{
+			name:     "reader macros",
+			source:   "'a @b ^c #_d #?(e) #inst f",
+			expected: `reader-macro:"'" symbol:"a" none:" " reader-macro:"@" symbol:"b" none:" " reader-macro:"^" symbol:"c" none:" " reader-macro:"#_" symbol:"d" none:" " reader-macro:"#?" delimiter:"(" symbol:"e" delimiter:")" none:" " tag:"#inst" none:" " symbol:"f"`,
+		},
+		{
+			name:     "comments",
+			source:   "; a\nb , c",
+			expected: `comment:"; a" none:"\n" symbol:"b" none:" , " symbol:"c"`,
+		},
+		{
+			name:     "invalid tokens",
+			source:   `"a 1e 0x 36rZZ 99r1 \`,
+			expected: `invalid:"\"a 1e 0x 36rZZ 99r1 \\"`,
+		},
+		{
+			name:     "invalid literals",
+			source:   `1e 0x 99r1 "\q" : #`,
+			expected: `invalid:"1e" none:" " invalid:"0x" none:" " invalid:"99r1" none:" " invalid:"\"\\q\"" none:" " invalid:":" none:" " invalid:"#"`,
+		},
+		{
+			name:     "scheme",
+			source:   "#t #| x |# #| y",
+			opts:     []Option{TokenOptions(token.WithDialect(token.SchemeR7RS()))},
+			expected: `constant:"#t" none:" " comment:"#| x |#" none:" " invalid:"#| y"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parts []string
+			for _, s := range Segments(tt.source, tt.opts...) {
+				parts = append(parts, fmt.Sprintf("%s:%q", s.Class, tt.source[s.Start:s.End]))
+			}
+			requireEqual(t, tt.expected, strings.Join(parts, " "))
+		})
+	}
+}
+
+func TestANSI(t *testing.T) {
+	theme := Theme{
+		ClassKeyword: {Color: "#ff0000", Bold: true},
+		ClassInvalid: {Underline: true, Italic: true},
+	}
+	opt := WithTheme(theme)
+	theme[ClassKeyword] = Style{}
+	var b strings.Builder
+	if err := ANSI(&b, `(a :b "c`, opt); err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, "(a \x1b[1;38;2;255;0;0m:b\x1b[0m \x1b[3;4m\"c\x1b[0m", b.String())
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "classes",
+			expected: `<span class="gasp-delimiter">(</span><span class="gasp-symbol">&lt;</span> <span class="gasp-string">&#34;&amp;&#34;</span><span class="gasp-delimiter">)</span>`,
+		},
+		{
+			name:     "class prefix",
+			opts:     []Option{ClassPrefix("hl-")},
+			expected: `<span class="hl-delimiter">(</span><span class="hl-symbol">&lt;</span> <span class="hl-string">&#34;&amp;&#34;</span><span class="hl-delimiter">)</span>`,
+		},
+		{
+			name:     "inline styles",
+			opts:     []Option{InlineStyles(), WithTheme(Theme{ClassString: {Color: "#00ff00", Bold: true}})},
+			expected: `(&lt; <span style="color:#00ff00;font-weight:bold">&#34;&amp;&#34;</span>)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := HTML(&b, `(< "&")`, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, b.String())
+		})
+	}
+}
+
+func TestCSS(t *testing.T) {
+	theme := Theme{
+		ClassComment: {Color: "#999999", Italic: true},
+		ClassSymbol:  {Color: "invalid"},
+		ClassInvalid: {Underline: true},
+	}
+	requireEqual(t, ".x-comment{color:#999999;font-style:italic}\n.x-invalid{text-decoration:underline}\n", theme.CSS("x-"))
+}
+
+func TestClassPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	_ = Class(255).String()
+}
+
+func requireEqual[T comparable](tb testing.TB, expected, received T) {
+	tb.Helper()
+	if expected != received {
+		tb.Fatalf("expected %v, received %v", expected, received)
+	}
+}
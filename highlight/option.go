@@ -0,0 +1,49 @@
+package highlight
+
+import (
+	"maps"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+type Option func(*options)
+
+type options struct {
+	token        []token.Option
+	theme        Theme
+	classPrefix  string
+	inlineStyles bool
+}
+
+func TokenOptions(opts ...token.Option) Option {
+	return func(o *options) {
+		o.token = append(o.token, opts...)
+	}
+}
+
+func WithTheme(theme Theme) Option {
+	theme = maps.Clone(theme)
+	return func(o *options) {
+		o.theme = theme
+	}
+}
+
+func ClassPrefix(prefix string) Option {
+	return func(o *options) {
+		o.classPrefix = prefix
+	}
+}
+
+func InlineStyles() Option {
+	return func(o *options) {
+		o.inlineStyles = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{theme: DefaultTheme(), classPrefix: "gasp-"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
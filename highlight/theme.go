@@ -0,0 +1,91 @@
+package highlight
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Style struct {
+	Color     string
+	Bold      bool
+	Italic    bool
+	Underline bool
+}
+
+func (s Style) ansi() string {
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if r, g, b, ok := parseColor(s.Color); ok {
+		params = append(params, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+func (s Style) css() string {
+	var decls []string
+	if _, _, _, ok := parseColor(s.Color); ok {
+		decls = append(decls, "color:"+s.Color)
+	}
+	if s.Bold {
+		decls = append(decls, "font-weight:bold")
+	}
+	if s.Italic {
+		decls = append(decls, "font-style:italic")
+	}
+	if s.Underline {
+		decls = append(decls, "text-decoration:underline")
+	}
+	return strings.Join(decls, ";")
+}
+
+type Theme map[Class]Style
+
+func DefaultTheme() Theme {
+	return Theme{
+		ClassDelimiter:   {Color: "#839496"},
+		ClassReaderMacro: {Color: "#cb4b16"},
+		ClassKeyword:     {Color: "#268bd2"},
+		ClassConstant:    {Color: "#6c71c4"},
+		ClassNumber:      {Color: "#d33682"},
+		ClassString:      {Color: "#2aa198"},
+		ClassCharacter:   {Color: "#2aa198"},
+		ClassRegex:       {Color: "#859900"},
+		ClassTag:         {Color: "#b58900"},
+		ClassComment:     {Color: "#93a1a1", Italic: true},
+		ClassInvalid:     {Color: "#dc322f", Underline: true},
+	}
+}
+
+func (t Theme) CSS(prefix string) string {
+	var b strings.Builder
+	for c := ClassNone; c <= ClassInvalid; c++ {
+		if decls := t[c].css(); decls != "" {
+			fmt.Fprintf(&b, ".%s%s{%s}\n", prefix, c, decls)
+		}
+	}
+	return b.String()
+}
+
+func parseColor(s string) (r, g, b uint8, ok bool) {
+	hex, found := strings.CutPrefix(s, "#")
+	if !found || len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
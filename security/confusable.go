@@ -0,0 +1,24 @@
+package security
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:generate go run gen.go
+
+func skeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if isInvisible(r) || isBidiControl(r) {
+			continue
+		}
+		if c, ok := confusables[r]; ok {
+			b.WriteString(c)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
@@ -0,0 +1,123 @@
+//go:build ignore
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const defaultURL = "https://www.unicode.org/Public/security/latest/confusables.txt"
+
+type entry struct {
+	from rune
+	to   string
+}
+
+func main() {
+	src := flag.String("src", defaultURL, "path or URL of confusables.txt")
+	out := flag.String("out", "confusables_table.go", "output file")
+	flag.Parse()
+
+	r, err := open(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	version, entries, err := parse(r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if version == "" {
+		log.Fatalf("%s: missing \"# Version:\" header, not an official confusables.txt", *src)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen.go from confusables.txt; DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Unicode version: %s\n", version)
+	fmt.Fprintf(&b, "\npackage security\n\nvar confusables = map[rune]string{\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\t0x%04X: %s,\n", e.from, strconv.QuoteToASCII(e.to))
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func open(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(src)
+	}
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func parse(r io.Reader) (string, []entry, error) {
+	var version string
+	var entries []entry
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimPrefix(s.Text(), "\uFEFF")
+		if v, ok := strings.CutPrefix(line, "# Version: "); ok {
+			version = strings.TrimSpace(v)
+		}
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Split(line, ";")
+		if len(fields) < 3 {
+			continue
+		}
+
+		from, err := codePoints(fields[0])
+		if err != nil {
+			return "", nil, err
+		}
+		if len(from) != 1 {
+			return "", nil, fmt.Errorf("unexpected source sequence %q", fields[0])
+		}
+		to, err := codePoints(fields[1])
+		if err != nil {
+			return "", nil, err
+		}
+		entries = append(entries, entry{from: from[0], to: string(to)})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return int(a.from - b.from)
+	})
+	return version, entries, s.Err()
+}
+
+func codePoints(s string) ([]rune, error) {
+	var runes []rune
+	for _, f := range strings.Fields(s) {
+		n, err := strconv.ParseUint(f, 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, rune(n))
+	}
+	return runes, nil
+}
@@ -0,0 +1,66 @@
+package security
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+type script struct {
+	name  string
+	table *unicode.RangeTable
+}
+
+var scriptTables = func() []script {
+	var s []script
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" {
+			s = append(s, script{name: name, table: table})
+		}
+	}
+	slices.SortFunc(s, func(a, b script) int {
+		return len(b.table.R16) + len(b.table.R32) - len(a.table.R16) - len(a.table.R32)
+	})
+	return s
+}()
+
+var allowedScriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+func scripts(s string) []string {
+	var found []string
+	for _, r := range s {
+		name := scriptOf(r)
+		if name != "" && !slices.Contains(found, name) {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+func scriptOf(r rune) string {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return "Latin"
+		}
+		return ""
+	}
+	for _, s := range scriptTables {
+		if unicode.Is(s.table, r) {
+			return s.name
+		}
+	}
+	return ""
+}
+
+func allowedScripts(found []string) bool {
+	for _, set := range allowedScriptSets {
+		if !slices.ContainsFunc(found, func(name string) bool { return !slices.Contains(set, name) }) {
+			return true
+		}
+	}
+	return false
+}
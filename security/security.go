@@ -0,0 +1,147 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func Reject() token.Option {
+	return token.Validate(validate)
+}
+
+func Scan(src string, opts ...token.Option) token.Diagnostics {
+	var buf token.TokenBuffer
+	var diags token.Diagnostics
+	err := buf.Tokenize(src, append(slices.Clip(opts), Reject())...)
+	errors.As(err, &diags)
+
+	var findings token.Diagnostics
+	for _, d := range diags {
+		switch d.Code {
+		case token.CodeBidiControl, token.CodeInvisibleCharacter, token.CodeMixedScript:
+			d.Severity = token.SeverityWarning
+			findings = append(findings, d)
+		}
+	}
+
+	type ident struct {
+		text string
+		span token.Span
+	}
+	seen := make(map[string]ident)
+	cursor := token.NewCursor()
+	for i := range buf.Len() {
+		text := src[buf.Start(i):buf.End(i)]
+		span := cursor.Advance(text)
+		if k := buf.Kind(i); k != token.KindSymbol && k != token.KindKeyword {
+			continue
+		}
+
+		sk := skeleton(text)
+		if prev, ok := seen[sk]; !ok {
+			seen[sk] = ident{text: text, span: span}
+		} else if prev.text != text {
+			findings = append(findings, confusable(span, fmt.Sprintf("%q is confusable with %q at %v", text, prev.text, prev.span.Start)))
+			continue
+		}
+		if !isASCII(text) && isASCII(sk) && len(scripts(text)) == 1 {
+			findings = append(findings, confusable(span, fmt.Sprintf("%q is confusable with %q", text, sk)))
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b token.Diagnostic) int {
+		return a.Span.Start.Offset - b.Span.Start.Offset
+	})
+	return findings
+}
+
+func validate(tok token.Token, text string) token.Diagnostics {
+	var ds token.Diagnostics
+	for i, r := range text {
+		var code token.Code
+		var msg string
+		switch {
+		case isBidiControl(r):
+			code, msg = token.CodeBidiControl, fmt.Sprintf("bidirectional control character %U", r)
+		case isInvisible(r):
+			code, msg = token.CodeInvisibleCharacter, fmt.Sprintf("invisible character %U", r)
+		default:
+			continue
+		}
+		span := relSpan(i, i+utf8.RuneLen(r))
+		ds = append(ds, token.Diagnostic{
+			Severity: token.SeverityError,
+			Code:     code,
+			Span:     span,
+			Message:  msg,
+			Fix:      &token.Fix{Message: "remove it", Span: span},
+		})
+	}
+
+	if k := tok.Kind(); k == token.KindSymbol || k == token.KindKeyword {
+		if s := scripts(text); len(s) > 1 && !allowedScripts(s) {
+			ds = append(ds, token.Diagnostic{
+				Severity: token.SeverityError,
+				Code:     token.CodeMixedScript,
+				Span:     relSpan(0, len(text)),
+				Message:  fmt.Sprintf("%s mixes %s and %s scripts", identKind(k), s[0], s[1]),
+			})
+		}
+	}
+	return ds
+}
+
+func identKind(k token.Kind) string {
+	if k == token.KindKeyword {
+		return "keyword"
+	}
+	return "symbol"
+}
+
+func confusable(span token.Span, msg string) token.Diagnostic {
+	return token.Diagnostic{
+		Severity: token.SeverityWarning,
+		Code:     token.CodeConfusable,
+		Span:     span,
+		Message:  msg,
+	}
+}
+
+func relSpan(from, to int) token.Span {
+	return token.Span{Start: token.Pos{Offset: from}, End: token.Pos{Offset: to}}
+}
+
+func isBidiControl(r rune) bool {
+	switch r {
+	case '\u061C', '\u200E', '\u200F',
+		'\u202A', '\u202B', '\u202C', '\u202D', '\u202E',
+		'\u2066', '\u2067', '\u2068', '\u2069':
+		return true
+	default:
+		return false
+	}
+}
+
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u00AD', '\u034F', '\u115F', '\u1160', '\u17B4', '\u17B5', '\u180E',
+		'\u200B', '\u200C', '\u200D', '\u2060', '\u2061', '\u2062', '\u2063', '\u2064',
+		'\u3164', '\uFEFF', '\uFFA0':
+		return true
+	default:
+		return false
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jussi-kalliokoski/gasp/ast"
+	"github.com/jussi-kalliokoski/gasp/token"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:   "clean",
+			source: "(defn greet [name] (str \"héllo, \" name)) ; 日本語",
+		},
+		{
+			name:   "bidi control in string",
+			source: "(access \"user\u202E \u2066// admin\u2069\u2066\")",
+			expected: []string{
+				"warning[bidi-control] 1:14-1:15: bidirectional control character U+202E",
+				"warning[bidi-control] 1:16-1:17: bidirectional control character U+2066",
+				"warning[bidi-control] 1:25-1:26: bidirectional control character U+2069",
+				"warning[bidi-control] 1:26-1:27: bidirectional control character U+2066",
+			},
+		},
+		{
+			name:   "bidi control in code",
+			source: "(a \u200Fb)",
+			expected: []string{
+				"warning[bidi-control] 1:4-1:5: bidirectional control character U+200F",
+			},
+		},
+		{
+			name:   "zero width in comment",
+			source: ";; ok\u200B\n(a)",
+			expected: []string{
+				"warning[invisible-character] 1:6-1:7: invisible character U+200B",
+			},
+		},
+		{
+			name:   "mixed script symbol",
+			source: "(p\u0430ypal :\u03B1lpha)",
+			expected: []string{
+				"warning[mixed-script] 1:2-1:8: symbol mixes Latin and Cyrillic scripts",
+				"warning[mixed-script] 1:9-1:15: keyword mixes Greek and Latin scripts",
+			},
+		},
+		{
+			name:   "allowed script mix",
+			source: "(日本語かなカナ abc漢字)",
+		},
+		{
+			name:   "confusable identifiers",
+			source: "(def paypal 1)\n(def p\u0430ypal 2)\n(def paypa1 3)",
+			expected: []string{
+				"warning[mixed-script] 2:6-2:12: symbol mixes Latin and Cyrillic scripts",
+				"warning[confusable] 2:6-2:12: \"p\u0430ypal\" is confusable with \"paypal\" at 1:6",
+				"warning[confusable] 3:6-3:12: \"paypa1\" is confusable with \"paypal\" at 1:6",
+			},
+		},
+		{
+			name:   "whole script confusable",
+			source: "(\u0441\u043E\u0440 :\u0430)",
+			expected: []string{
+				"warning[confusable] 1:2-1:5: \"\u0441\u043E\u0440\" is confusable with \"cop\"",
+				"warning[confusable] 1:6-1:8: \":\u0430\" is confusable with \":a\"",
+			},
+		},
+		{
+			name:   "fullwidth",
+			source: "(ace \uFF41\uFF43\uFF45)",
+			expected: []string{
+				"warning[confusable] 1:6-1:9: \"\uFF41\uFF43\uFF45\" is confusable with \"ace\" at 1:2",
+			},
+		},
+		{
+			name:   "multi-character prototype",
+			source: "(modern rnodern)",
+			expected: []string{
+				"warning[confusable] 1:9-1:16: \"rnodern\" is confusable with \"modern\" at 1:2",
+			},
+		},
+		{
+			name:   "mathematical alphanumerics",
+			source: "(bad \U0001D41B\U0001D41A\U0001D41D)",
+			expected: []string{
+				"warning[confusable] 1:6-1:9: \"\U0001D41B\U0001D41A\U0001D41D\" is confusable with \"bad\" at 1:2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Scan(tt.source)
+			requireEqual(t, len(tt.expected), len(findings))
+			for i, d := range findings {
+				requireEqual(t, tt.expected[i], formatDiagnostic(d))
+			}
+		})
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"caf\u00E9", "cafe\u0301"},
+		{"\u0441\u043E\u0440", "cop"},
+		{"m", "rn"},
+		{"\u212B", "A\u030A"},
+		{"a\u200Bb", "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			requireEqual(t, skeleton(tt.a), skeleton(tt.b))
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:   "clean",
+			source: "(a \"b\")",
+		},
+		{
+			name:     "bidi control",
+			source:   "\"a\u202Eb\"",
+			expected: "1:3: bidirectional control character U+202E",
+		},
+		{
+			name:     "mixed script",
+			source:   "(p\u0430ypal)",
+			expected: "1:2: symbol mixes Latin and Cyrillic scripts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf token.TokenBuffer
+			err := buf.Tokenize(tt.source, Reject())
+			_, readErr := ast.Read(tt.source, ast.TokenOptions(Reject()))
+			if tt.expected == "" {
+				requireEqual(t, nil, err)
+				requireEqual(t, nil, readErr)
+				return
+			}
+			var diags token.Diagnostics
+			if !errors.As(err, &diags) {
+				t.Fatalf("expected diagnostics, received %v", err)
+			}
+			requireEqual(t, tt.expected, err.Error())
+			requireEqual(t, token.SeverityError, diags[0].Severity)
+			requireEqual(t, tt.expected, readErr.Error())
+		})
+	}
+}
+
+func formatDiagnostic(d token.Diagnostic) string {
+	return fmt.Sprintf("%v[%v] %v: %s", d.Severity, d.Code, d.Span, d.Message)
+}
+
+func requireEqual[T comparable](tb testing.TB, expected, received T) {
+	tb.Helper()
+	if expected != received {
+		tb.Fatalf("expected %v, received %v", expected, received)
+	}
+}
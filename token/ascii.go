@@ -0,0 +1,55 @@
+package token
+
+import (
+	"unicode/utf8"
+)
+
+type charClass uint8
+
+const (
+	classWhitespace charClass = 1 << iota
+	classSymbolStart
+	classSymbolContinue
+)
+
+type asciiTable [utf8.RuneSelf]charClass
+
+func newASCIITable(d *Dialect) asciiTable {
+	t := tokenizer{dialect: d}
+	var table asciiTable
+	for c := rune(0); c < utf8.RuneSelf; c++ {
+		var class charClass
+		if t.isWhitespaceRune(c) {
+			class |= classWhitespace
+		}
+		if t.isSymbolStartRune(c) {
+			class |= classSymbolStart
+		}
+		if t.isSymbolContinueRune(c) {
+			class |= classSymbolContinue
+		}
+		table[c] = class
+	}
+	return table
+}
+
+func (t *tokenizer) skipASCII(class charClass) {
+	i := 0
+	for i < len(t.s) && t.s[i] < utf8.RuneSelf && t.ascii[t.s[i]]&class != 0 {
+		i++
+	}
+	t.skip(i)
+}
+
+func (t *tokenizer) skipUntil(a, b byte) {
+	i := 0
+	for i < len(t.s) && t.s[i] != a && t.s[i] != b {
+		i++
+	}
+	t.skip(i)
+}
+
+func (t *tokenizer) skip(n int) {
+	t.s = t.s[n:]
+	t.posWithinTok += uint32(n)
+}
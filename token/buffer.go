@@ -0,0 +1,125 @@
+package token
+
+import (
+	"sort"
+)
+
+type TokenBuffer struct {
+	kinds   []Kind
+	starts  []uint32
+	payload []uint64
+	end     uint32
+}
+
+func NewTokenBuffer(capacity int) *TokenBuffer {
+	return &TokenBuffer{
+		kinds:   make([]Kind, 0, capacity),
+		starts:  make([]uint32, 0, capacity),
+		payload: make([]uint64, 0, capacity),
+	}
+}
+
+func (b *TokenBuffer) Tokenize(s string, opts ...Option) error {
+	b.Reset()
+	sc := newScanner(s, opts)
+	for {
+		token, ok := sc.next()
+		if !ok {
+			return sc.err()
+		}
+		b.append(token)
+	}
+}
+
+func (b *TokenBuffer) ConsumeToken(t Token) {
+	b.append(t)
+}
+
+func (b *TokenBuffer) Reset() {
+	b.kinds = b.kinds[:0]
+	b.starts = b.starts[:0]
+	b.payload = b.payload[:0]
+	b.end = 0
+}
+
+func (b *TokenBuffer) Len() int {
+	return len(b.kinds)
+}
+
+func (b *TokenBuffer) Kind(i int) Kind {
+	return b.kinds[i]
+}
+
+func (b *TokenBuffer) Start(i int) int {
+	return int(b.starts[i])
+}
+
+func (b *TokenBuffer) End(i int) int {
+	_ = b.starts[i]
+	if i+1 < len(b.starts) {
+		return int(b.starts[i+1])
+	}
+	return int(b.end)
+}
+
+func (b *TokenBuffer) At(i int) Token {
+	t := Token{kind: b.kinds[i], len: uint32(b.End(i) - b.Start(i))}
+	p := b.payload[i]
+	switch t.kind {
+	case KindLiteral:
+		t.literal = Literal{
+			kind:   LiteralKind(p),
+			base:   Base(p >> 8),
+			flags:  literalFlags(p >> 16),
+			offset: uint32(p >> 32),
+		}
+	case KindSymbol, KindKeyword, KindTag:
+		t.ident = ident{flags: identFlags(p), nsLen: uint32(p >> 32)}
+	case KindBlockComment:
+		t.comment = commentFlags(p)
+	}
+	return t
+}
+
+func (b *TokenBuffer) Index(offset int) int {
+	if offset < 0 || offset >= int(b.end) {
+		return -1
+	}
+	return sort.Search(len(b.starts), func(i int) bool {
+		return int(b.starts[i]) > offset
+	}) - 1
+}
+
+func (b *TokenBuffer) Tokens() []Token {
+	tokens := make([]Token, b.Len())
+	for i := range tokens {
+		tokens[i] = b.At(i)
+	}
+	return tokens
+}
+
+func (b *TokenBuffer) append(t Token) {
+	var p uint64
+	switch t.kind {
+	case KindLiteral:
+		l := t.literal
+		p = uint64(l.kind) | uint64(l.base)<<8 | uint64(l.flags)<<16 | uint64(l.offset)<<32
+	case KindSymbol, KindKeyword, KindTag:
+		p = uint64(t.ident.flags) | uint64(t.ident.nsLen)<<32
+	case KindBlockComment:
+		p = uint64(t.comment)
+	}
+	b.kinds = append(b.kinds, t.kind)
+	b.starts = append(b.starts, b.end)
+	b.payload = append(b.payload, p)
+	b.end += t.len
+}
+
+func (b *TokenBuffer) appendBuffer(other *TokenBuffer) {
+	b.kinds = append(b.kinds, other.kinds...)
+	b.payload = append(b.payload, other.payload...)
+	for _, start := range other.starts {
+		b.starts = append(b.starts, b.end+start)
+	}
+	b.end += other.end
+}
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"strings"
+	"testing"
+)
+
+const benchmarkSource = `(ns example.core
+  (:require [clojure.string :as str]))
+
+;; A small, representative chunk of Clojure.
+(defn greet
+  "Greets someone by name."
+  [{:keys [first-name last-name] :as person}]
+  (let [full (str/join " " [first-name last-name])]
+    (println (format "Hello, %s!" full))
+    #{:greeted full 0x1F 1/3 3.14e-2 \newline}))
+
+(defmacro unless [test & body]
+  ` + "`" + `(if ~test nil (do ~@body)))
+
+#_(comment (greet {:first-name "Ada" :last-name "Lovelace"}))
+(def pattern #"[a-z]+\d*")
+(def lookup ^:private {::id 42N, :ns/key 'sym, :empty ""})
+`
+
+func TestTokenBuffer(t *testing.T) {
+	source := benchmarkSource + `#my.ns/tag [1] "unterminated \q`
+
+	var sc sliceConsumer
+	expectedErr := Tokenize(&sc, source)
+
+	var b TokenBuffer
+	err := b.Tokenize(source)
+	requireEqual(t, expectedErr.Error(), err.Error())
+	diffTokens(t, source, sc.Tokens(), b.Tokens())
+
+	offset := 0
+	for i, token := range sc.Tokens() {
+		requireEqual(t, offset, b.Start(i))
+		requireEqual(t, offset+token.Len(), b.End(i))
+		requireEqual(t, token.Kind(), b.Kind(i))
+		requireEqual(t, i, b.Index(offset))
+		requireEqual(t, i, b.Index(offset+token.Len()-1))
+		offset += token.Len()
+	}
+	requireEqual(t, -1, b.Index(-1))
+	requireEqual(t, -1, b.Index(len(source)))
+
+	var consumed TokenBuffer
+	for _, token := range sc.Tokens() {
+		consumed.ConsumeToken(token)
+	}
+	diffTokens(t, source, sc.Tokens(), consumed.Tokens())
+}
+
+func TestTokenBufferReuse(t *testing.T) {
+	source := strings.Repeat(benchmarkSource, 4)
+	b := NewTokenBuffer(0)
+	if err := b.Tokenize(source); err != nil {
+		t.Fatal(err)
+	}
+	n := b.Len()
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if err := b.Tokenize(source); err != nil {
+			t.Fatal(err)
+		}
+	})
+	requireEqual(t, n, b.Len())
+	if allocs > 2 {
+		t.Fatalf("expected at most 2 allocations, received %v", allocs)
+	}
+}
+
+func TestTokenBufferEmpty(t *testing.T) {
+	var b TokenBuffer
+	if err := b.Tokenize(""); err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, 0, b.Len())
+	requireEqual(t, -1, b.Index(0))
+}
+
+func BenchmarkTokenizeConsumer(b *testing.B) {
+	source := strings.Repeat(benchmarkSource, 1000)
+	b.SetBytes(int64(len(source)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var sc sliceConsumer
+		_ = Tokenize(&sc, source)
+	}
+}
+
+func BenchmarkTokenBuffer(b *testing.B) {
+	source := strings.Repeat(benchmarkSource, 1000)
+	b.SetBytes(int64(len(source)))
+	b.ReportAllocs()
+	var buf TokenBuffer
+	for i := 0; i < b.N; i++ {
+		_ = buf.Tokenize(source)
+	}
+}
@@ -0,0 +1,98 @@
+package token
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadCorpus(tb testing.TB) map[string]string {
+	tb.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "corpus", "*.clj"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	corpus := make(map[string]string, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		corpus[filepath.Base(path)] = string(b)
+	}
+	return corpus
+}
+
+func TestCorpus(t *testing.T) {
+	for name, source := range loadCorpus(t) {
+		t.Run(name, func(t *testing.T) {
+			var sc sliceConsumer
+			if err := Tokenize(&sc, source); err != nil {
+				t.Fatal(err)
+			}
+
+			tokenizer := NewTokenizer(strings.NewReader(source))
+			var received []Token
+			for token := range tokenizer.All() {
+				received = append(received, token)
+			}
+			diffTokens(t, source, sc.Tokens(), received)
+
+			var parallel sliceConsumer
+			if err := tokenizeChunks(&parallel, source, 256, nil); err != nil {
+				t.Fatal(err)
+			}
+			diffTokens(t, source, sc.Tokens(), parallel.Tokens())
+			requireEqual(t, len(sc.Tokens()), len(parallel.Tokens()))
+		})
+	}
+}
+
+func BenchmarkCorpus(b *testing.B) {
+	corpus := loadCorpus(b)
+	var all strings.Builder
+	for _, source := range corpus {
+		all.WriteString(source)
+		all.WriteString("\n")
+	}
+	source := strings.Repeat(all.String(), 100)
+
+	b.Run("consumer", func(b *testing.B) {
+		b.SetBytes(int64(len(source)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var sc sliceConsumer
+			_ = Tokenize(&sc, source)
+		}
+	})
+
+	b.Run("buffer", func(b *testing.B) {
+		b.SetBytes(int64(len(source)))
+		b.ReportAllocs()
+		var buf TokenBuffer
+		for i := 0; i < b.N; i++ {
+			_ = buf.Tokenize(source)
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		b.SetBytes(int64(len(source)))
+		b.ReportAllocs()
+		var buf TokenBuffer
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			_ = TokenizeParallel(&buf, source)
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		b.SetBytes(int64(len(source)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tokenizer := NewTokenizer(strings.NewReader(source))
+			for range tokenizer.All() {
+			}
+		}
+	})
+}
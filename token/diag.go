@@ -0,0 +1,327 @@
+package token
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type Severity uint8
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		panic(fmt.Errorf("unknown severity: %d", s))
+	}
+}
+
+type Code uint8
+
+const (
+	CodeUnexpectedInput Code = iota + 1
+	CodeUnknownDispatch
+	CodeEmptyInteger
+	CodeInvalidRadix
+	CodeEmptyExponent
+	CodeUnterminatedString
+	CodeInvalidEscape
+	CodeMissingCharacter
+	CodeUnterminatedRegex
+	CodeUnterminatedComment
+	CodeBidiControl
+	CodeInvisibleCharacter
+	CodeMixedScript
+	CodeConfusable
+)
+
+func (c Code) String() string {
+	switch c {
+	case CodeUnexpectedInput:
+		return "unexpected-input"
+	case CodeUnknownDispatch:
+		return "unknown-dispatch"
+	case CodeEmptyInteger:
+		return "empty-integer"
+	case CodeInvalidRadix:
+		return "invalid-radix"
+	case CodeEmptyExponent:
+		return "empty-exponent"
+	case CodeUnterminatedString:
+		return "unterminated-string"
+	case CodeInvalidEscape:
+		return "invalid-escape"
+	case CodeMissingCharacter:
+		return "missing-character"
+	case CodeUnterminatedRegex:
+		return "unterminated-regex"
+	case CodeUnterminatedComment:
+		return "unterminated-comment"
+	case CodeBidiControl:
+		return "bidi-control"
+	case CodeInvisibleCharacter:
+		return "invisible-character"
+	case CodeMixedScript:
+		return "mixed-script"
+	case CodeConfusable:
+		return "confusable"
+	default:
+		panic(fmt.Errorf("unknown code: %d", c))
+	}
+}
+
+type Fix struct {
+	Message     string
+	Span        Span
+	Replacement string
+}
+
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+	Span     Span
+	Message  string
+	Fix      *Fix
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%v: %s", d.Span.Start, d.Message)
+}
+
+func (d Diagnostic) Render(w io.Writer, name, src string) error {
+	var b strings.Builder
+
+	location := d.Span.Start.String()
+	if name != "" {
+		location = name + ":" + location
+	}
+	line := lineAt(src, d.Span.Start.Offset)
+	lineNo := strconv.Itoa(d.Span.Start.Line)
+	gutter := strings.Repeat(" ", len(lineNo))
+
+	fmt.Fprintf(&b, "%v[%v]: %s\n", d.Severity, d.Code, d.Message)
+	fmt.Fprintf(&b, "%s--> %s\n", gutter, location)
+	fmt.Fprintf(&b, "%s |\n", gutter)
+	fmt.Fprintf(&b, "%s | %s\n", lineNo, line)
+	fmt.Fprintf(&b, "%s | %s\n", gutter, underline(line, d.Span))
+	if d.Fix != nil {
+		fmt.Fprintf(&b, "%s = help: %s\n", gutter, d.Fix.Message)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	switch len(ds) {
+	case 0:
+		return "no errors"
+	case 1:
+		return ds[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", ds[0].Error(), len(ds)-1)
+	}
+}
+
+func (ds Diagnostics) Err() error {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return ds
+		}
+	}
+	return nil
+}
+
+func (ds Diagnostics) Render(w io.Writer, name, src string) error {
+	for i, d := range ds {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := d.Render(w, name, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds Diagnostics) locate(start Cursor, text string) {
+	at := func(offset int) Pos {
+		c := start
+		c.Advance(text[:offset])
+		return c.Pos()
+	}
+	for i := range ds {
+		d := &ds[i]
+		d.Span = Span{Start: at(d.Span.Start.Offset), End: at(d.Span.End.Offset)}
+		if d.Fix != nil {
+			d.Fix.Span = Span{Start: at(d.Fix.Span.Start.Offset), End: at(d.Fix.Span.End.Offset)}
+		}
+	}
+}
+
+func (ds Diagnostics) shift(base Pos) {
+	at := func(p Pos) Pos {
+		if p.Line == 1 {
+			p.Column += base.Column - 1
+			p.UTF16Column += base.UTF16Column - 1
+		}
+		p.Offset += base.Offset
+		p.Line += base.Line - 1
+		return p
+	}
+	for i := range ds {
+		d := &ds[i]
+		d.Span = Span{Start: at(d.Span.Start), End: at(d.Span.End)}
+		if d.Fix != nil {
+			d.Fix.Span = Span{Start: at(d.Fix.Span.Start), End: at(d.Fix.Span.End)}
+		}
+	}
+}
+
+func (o *options) diagnose(tok Token, text string) Diagnostics {
+	ds := diagnose(tok, text)
+	for _, v := range o.validators {
+		ds = append(ds, v(tok, text)...)
+	}
+	return ds
+}
+
+func diagnose(tok Token, text string) Diagnostics {
+	var ds Diagnostics
+	report := func(code Code, from, to int, fix *Fix, format string, args ...any) {
+		ds = append(ds, Diagnostic{
+			Severity: SeverityError,
+			Code:     code,
+			Span:     relSpan(from, to),
+			Message:  fmt.Sprintf(format, args...),
+			Fix:      fix,
+		})
+	}
+	end := len(text)
+
+	switch tok.Kind() {
+	case KindInvalid:
+		report(CodeUnexpectedInput, 0, end, &Fix{Message: "remove it", Span: relSpan(0, end)}, "unexpected %q", text)
+	case KindDispatch:
+		report(CodeUnknownDispatch, 0, end, nil, "unknown dispatch macro")
+	case KindBlockComment:
+		if tok.BlockComment().Unterminated() {
+			closing := strings.Repeat("|#", commentDepth(text))
+			fix := &Fix{Message: "close the comment", Span: relSpan(end, end), Replacement: closing}
+			report(CodeUnterminatedComment, 0, end, fix, "unterminated block comment")
+		}
+	case KindLiteral:
+		lit := tok.Literal()
+		switch lit.Kind() {
+		case LiteralKindInteger:
+			if lit.Integer().EmptyInt() {
+				report(CodeEmptyInteger, 0, end, nil, "missing digits in integer literal")
+			}
+			if lit.Integer().InvalidRadix() {
+				report(CodeInvalidRadix, 0, end, nil, "radix must be between %d and %d", minBase, maxBase)
+			}
+		case LiteralKindFloat:
+			if lit.Float().EmptyExponent() {
+				at := strings.LastIndexAny(text, "eE") + 1
+				if at < end && (text[at] == '+' || text[at] == '-') {
+					at++
+				}
+				fix := &Fix{Message: "add exponent digits", Span: relSpan(at, at), Replacement: "0"}
+				report(CodeEmptyExponent, 0, end, fix, "missing exponent digits in float literal")
+			}
+		case LiteralKindString:
+			if lit.String().InvalidEscape() {
+				from := lit.String().InvalidEscapeOffset()
+				_, size, _ := scanEscape(text[from+1:])
+				to := from + 1 + size
+				fix := &Fix{Message: "escape the backslash", Span: relSpan(from, from+1), Replacement: `\\`}
+				report(CodeInvalidEscape, from, to, fix, "invalid escape sequence %s", text[from:to])
+			}
+			if lit.String().Unterminated() {
+				fix := &Fix{Message: "insert the closing quote", Span: relSpan(end, end), Replacement: `"`}
+				report(CodeUnterminatedString, 0, end, fix, "unterminated string literal")
+			}
+		case LiteralKindCharacter:
+			if lit.Character().MissingCharacter() {
+				report(CodeMissingCharacter, 0, end, nil, "missing character after backslash")
+			}
+		case LiteralKindRegex:
+			if lit.Regex().Unterminated() {
+				fix := &Fix{Message: "insert the closing quote", Span: relSpan(end, end), Replacement: `"`}
+				report(CodeUnterminatedRegex, 0, end, fix, "unterminated regex literal")
+			}
+		}
+	}
+
+	return ds
+}
+
+func commentDepth(text string) int {
+	depth := 0
+	for i := 0; i+1 < len(text); i++ {
+		switch text[i : i+2] {
+		case "#|":
+			depth++
+			i++
+		case "|#":
+			depth--
+			i++
+		}
+	}
+	return depth
+}
+
+func relSpan(from, to int) Span {
+	return Span{Start: Pos{Offset: from}, End: Pos{Offset: to}}
+}
+
+func lineAt(src string, offset int) string {
+	offset = min(offset, len(src))
+	start := 0
+	if i := strings.LastIndexAny(src[:offset], lineTerminators); i >= 0 {
+		_, size := utf8.DecodeRuneInString(src[i:])
+		start = i + size
+	}
+	end := strings.IndexAny(src[offset:], lineTerminators)
+	if end == -1 {
+		return src[start:]
+	}
+	return src[start : offset+end]
+}
+
+func underline(line string, span Span) string {
+	var b strings.Builder
+	col := 1
+	for _, r := range line {
+		if col >= span.Start.Column {
+			break
+		}
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+		col++
+	}
+
+	n := utf8.RuneCountInString(line) - span.Start.Column + 1
+	if span.End.Line == span.Start.Line {
+		n = span.End.Column - span.Start.Column
+	}
+	b.WriteString(strings.Repeat("^", max(1, n)))
+	return b.String()
+}
@@ -0,0 +1,230 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected []string
+	}{
+		{"(a 1.5 \"ok\")", nil},
+		{"\u0000", []string{`error[unexpected-input] 1:1-1:2: unexpected "\x00" (remove it at 1:1-1:2: "")`}},
+		{"#", []string{"error[unknown-dispatch] 1:1-1:2: unknown dispatch macro"}},
+		{"0x", []string{"error[empty-integer] 1:1-1:3: missing digits in integer literal"}},
+		{"37r1", []string{"error[invalid-radix] 1:1-1:5: radix must be between 2 and 36"}},
+		{"1e+M", []string{`error[empty-exponent] 1:1-1:5: missing exponent digits in float literal (add exponent digits at 1:4-1:4: "0")`}},
+		{`"a\qb"`, []string{`error[invalid-escape] 1:3-1:5: invalid escape sequence \q (escape the backslash at 1:3-1:4: "\\\\")`}},
+		{`"\u12"`, []string{`error[invalid-escape] 1:2-1:6: invalid escape sequence \u12 (escape the backslash at 1:2-1:3: "\\\\")`}},
+		{"(a\n  \"b", []string{`error[unterminated-string] 2:3-2:5: unterminated string literal (insert the closing quote at 2:5-2:5: "\"")`}},
+		{`"\q`, []string{
+			`error[invalid-escape] 1:2-1:4: invalid escape sequence \q (escape the backslash at 1:2-1:3: "\\\\")`,
+			`error[unterminated-string] 1:1-1:4: unterminated string literal (insert the closing quote at 1:4-1:4: "\"")`,
+		}},
+		{`a \`, []string{"error[missing-character] 1:3-1:4: missing character after backslash"}},
+		{`#"a`, []string{`error[unterminated-regex] 1:1-1:4: unterminated regex literal (insert the closing quote at 1:4-1:4: "\"")`}},
+		{"#| a #| b |#", []string{`error[unterminated-comment] 1:1-1:13: unterminated block comment (close the comment at 1:13-1:13: "|#")`}},
+		{"#| a #| b", []string{`error[unterminated-comment] 1:1-1:10: unterminated block comment (close the comment at 1:10-1:10: "|#|#")`}},
+		{"#| a |#", nil},
+		{"0x 1e", []string{
+			"error[empty-integer] 1:1-1:3: missing digits in integer literal",
+			`error[empty-exponent] 1:4-1:6: missing exponent digits in float literal (add exponent digits at 1:6-1:6: "0")`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			var sc sliceConsumer
+			err := Tokenize(&sc, tt.source, WithDialect(Dialect{BlockComments: true}))
+
+			var diags Diagnostics
+			if err != nil && !errors.As(err, &diags) {
+				t.Fatal(err)
+			}
+			if (err == nil) != (len(tt.expected) == 0) {
+				t.Fatalf("expected %d diagnostics, received error %v", len(tt.expected), err)
+			}
+
+			requireEqual(t, len(tt.expected), len(diags))
+			for i, d := range diags {
+				requireEqual(t, tt.expected[i], formatDiagnostic(d))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	source := "(ok\n  bad \"bad\")"
+	validate := Validate(func(tok Token, text string) Diagnostics {
+		if tok.Kind() != KindSymbol || text != "bad" {
+			return nil
+		}
+		return Diagnostics{{
+			Severity: SeverityWarning,
+			Code:     CodeConfusable,
+			Span:     Span{Start: Pos{Offset: 1}, End: Pos{Offset: 3}},
+			Message:  "bad symbol",
+		}}
+	})
+	expected := "warning[confusable] 2:4-2:6: bad symbol"
+
+	var sc sliceConsumer
+	requireEqual(t, nil, Tokenize(&sc, source, validate))
+
+	tokenizer := NewTokenizer(strings.NewReader(source), validate)
+	for range tokenizer.All() {
+	}
+	requireEqual(t, 1, len(tokenizer.Diagnostics()))
+	requireEqual(t, expected, formatDiagnostic(tokenizer.Diagnostics()[0]))
+
+	var buf TokenBuffer
+	err := buf.Tokenize(source, Validate(func(tok Token, text string) Diagnostics {
+		return Diagnostics{{Code: CodeBidiControl, Span: Span{End: Pos{Offset: len(text)}}, Message: "rejected"}}
+	}))
+	requireEqual(t, "1:1: rejected (and 6 more errors)", fmt.Sprint(err))
+}
+
+func TestFailFast(t *testing.T) {
+	source := `(a "b\q" 0x "c`
+
+	var sc sliceConsumer
+	err := Tokenize(&sc, source, FailFast())
+	var diags Diagnostics
+	if !errors.As(err, &diags) {
+		t.Fatalf("expected diagnostics, received %v", err)
+	}
+	requireEqual(t, 1, len(diags))
+	requireEqual(t, CodeInvalidEscape, diags[0].Code)
+	requireEqual(t, 3, len(sc.Tokens()))
+
+	sc = sliceConsumer{}
+	err = Tokenize(&sc, source)
+	if !errors.As(err, &diags) {
+		t.Fatalf("expected diagnostics, received %v", err)
+	}
+	requireEqual(t, 3, len(diags))
+	requireEqual(t, "1:6: invalid escape sequence \\q (and 2 more errors)", err.Error())
+
+	tokenizer := NewTokenizer(strings.NewReader(source), FailFast())
+	n := 0
+	for {
+		_, err := tokenizer.Next()
+		if errors.Is(err, io.EOF) {
+			t.Fatal("expected diagnostics before EOF")
+		}
+		if err != nil {
+			if !errors.As(err, &diags) {
+				t.Fatal(err)
+			}
+			break
+		}
+		n++
+	}
+	requireEqual(t, 3, n)
+	requireEqual(t, CodeInvalidEscape, diags[0].Code)
+	if _, err := tokenizer.Next(); !errors.As(err, &diags) {
+		t.Fatalf("expected the diagnostics to persist, received %v", err)
+	}
+	requireEqual(t, 1, len(tokenizer.Diagnostics()))
+}
+
+func TestRender(t *testing.T) {
+	source := "(defn f [x]\n\t(str \"a\\qb\" x))\n(g \"open"
+
+	var sc sliceConsumer
+	var diags Diagnostics
+	if !errors.As(Tokenize(&sc, source), &diags) {
+		t.Fatal("expected diagnostics")
+	}
+
+	var b strings.Builder
+	if err := diags.Render(&b, "core.clj", source); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"error[invalid-escape]: invalid escape sequence \\q",
+		" --> core.clj:2:9",
+		"  |",
+		"2 | \t(str \"a\\qb\" x))",
+		"  | \t       ^^",
+		"  = help: escape the backslash",
+		"",
+		"error[unterminated-string]: unterminated string literal",
+		" --> core.clj:3:4",
+		"  |",
+		"3 | (g \"open",
+		"  |    ^^^^^",
+		"  = help: insert the closing quote",
+		"",
+	}, "\n")
+	requireEqual(t, expected, b.String())
+}
+
+func TestRenderMultiLine(t *testing.T) {
+	d := Diagnostic{
+		Code:    CodeUnterminatedString,
+		Message: "unterminated string literal",
+		Span: Span{
+			Start: Pos{Offset: 1, Line: 1, Column: 2},
+			End:   Pos{Offset: 6, Line: 2, Column: 3},
+		},
+	}
+
+	var b strings.Builder
+	if err := d.Render(&b, "", "x\"abc\nde"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"error[unterminated-string]: unterminated string literal",
+		" --> 1:2",
+		"  |",
+		"1 | x\"abc",
+		"  |  ^^^^",
+		"",
+	}, "\n")
+	requireEqual(t, expected, b.String())
+}
+
+func TestRenderLineTerminators(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		line   string
+	}{
+		{"line separator", "a\u2028\"b\\q\"\u2029c", "\"b\\q\""},
+		{"carriage return", "a\r\"b\\q\"\rc", "\"b\\q\""},
+		{"next line", "a\u0085\"b\\q\"", "a\u0085\"b\\q\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sc sliceConsumer
+			var diags Diagnostics
+			if !errors.As(Tokenize(&sc, tt.source), &diags) {
+				t.Fatal("expected diagnostics")
+			}
+
+			var b strings.Builder
+			if err := diags.Render(&b, "", tt.source); err != nil {
+				t.Fatal(err)
+			}
+			_, line, _ := strings.Cut(strings.Split(b.String(), "\n")[3], "| ")
+			requireEqual(t, tt.line, line)
+		})
+	}
+}
+
+func formatDiagnostic(d Diagnostic) string {
+	s := fmt.Sprintf("%v[%v] %v: %s", d.Severity, d.Code, d.Span, d.Message)
+	if d.Fix != nil {
+		s += fmt.Sprintf(" (%s at %v: %q)", d.Fix.Message, d.Fix.Span, d.Fix.Replacement)
+	}
+	return s
+}
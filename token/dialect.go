@@ -0,0 +1,60 @@
+package token
+
+type CommaMode uint8
+
+const (
+	CommaInvalid CommaMode = iota
+	CommaWhitespace
+	CommaUnquote
+	CommaToken
+)
+
+type Dialect struct {
+	Booleans         bool
+	BlockComments    bool
+	DatumComments    bool
+	BracketsAsParens bool
+	HashCharacters   bool
+	DataOnly         bool
+	Comma            CommaMode
+	SymbolChars      string
+}
+
+func Clojure() Dialect {
+	return Dialect{
+		Comma: CommaWhitespace,
+	}
+}
+
+func EDN() Dialect {
+	return Dialect{
+		DataOnly: true,
+		Comma:    CommaWhitespace,
+	}
+}
+
+func SchemeR7RS() Dialect {
+	return Dialect{
+		Booleans:       true,
+		BlockComments:  true,
+		DatumComments:  true,
+		HashCharacters: true,
+		Comma:          CommaUnquote,
+		SymbolChars:    "$^~@",
+	}
+}
+
+func CommonLisp() Dialect {
+	return Dialect{
+		BlockComments:  true,
+		HashCharacters: true,
+		Comma:          CommaUnquote,
+		SymbolChars:    "$^~@[]{}",
+	}
+}
+
+func WithDialect(d Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
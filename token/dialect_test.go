@@ -0,0 +1,347 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		source   string
+		expected []Token
+	}{
+		{
+			name:    "clojure comma",
+			dialect: Clojure(),
+			source:  "{:a 1, :b 2}",
+			expected: []Token{
+				newToken(KindOpenBrace, 1),
+				newKeyword(2, 0, false),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindWhitespace, 2),
+				newKeyword(2, 0, false),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseBrace, 1),
+			},
+		},
+		{
+			name:    "invalid comma",
+			dialect: Dialect{Comma: CommaInvalid},
+			source:  "a,b",
+			expected: []Token{
+				newToken(KindSymbol, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+			},
+		},
+		{
+			name:    "comma tokens",
+			dialect: Dialect{Comma: CommaToken},
+			source:  "[1 ,,2]",
+			expected: []Token{
+				newToken(KindOpenBracket, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindWhitespace, 1),
+				newToken(KindComma, 1),
+				newToken(KindComma, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseBracket, 1),
+			},
+		},
+		{
+			name:    "clojure hash pipe",
+			dialect: Clojure(),
+			source:  "#|a|#",
+			expected: []Token{
+				newToken(KindDispatch, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindDispatch, 1),
+			},
+		},
+		{
+			name:    "clojure booleans are tags",
+			dialect: Clojure(),
+			source:  "#t",
+			expected: []Token{
+				newToken(KindTag, 2),
+			},
+		},
+		{
+			name:    "edn comma",
+			dialect: EDN(),
+			source:  "{:a 1, :b 2}",
+			expected: []Token{
+				newToken(KindOpenBrace, 1),
+				newKeyword(2, 0, false),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindWhitespace, 2),
+				newKeyword(2, 0, false),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseBrace, 1),
+			},
+		},
+		{
+			name:    "edn code syntax",
+			dialect: EDN(),
+			source:  "`a ~@b @c ^d 'e #(f) #\"g\" #'h #?i #{}",
+			expected: []Token{
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindDispatch, 1),
+				newToken(KindOpenParen, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindCloseParen, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindDispatch, 1),
+				newString(3),
+				newToken(KindWhitespace, 1),
+				newToken(KindDispatch, 1),
+				newToken(KindInvalid, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindDispatch, 1),
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindOpenSet, 2),
+				newToken(KindCloseBrace, 1),
+			},
+		},
+		{
+			name:    "scheme booleans",
+			dialect: SchemeR7RS(),
+			source:  "#t #f #true #false #tee",
+			expected: []Token{
+				newBoolean(2),
+				newToken(KindWhitespace, 1),
+				newBoolean(2),
+				newToken(KindWhitespace, 1),
+				newBoolean(5),
+				newToken(KindWhitespace, 1),
+				newBoolean(6),
+				newToken(KindWhitespace, 1),
+				newToken(KindTag, 4),
+			},
+		},
+		{
+			name:    "scheme block comments",
+			dialect: SchemeR7RS(),
+			source:  "a #| b #| nested |# c |# d",
+			expected: []Token{
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindBlockComment, 22),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+			},
+		},
+		{
+			name:    "scheme unterminated block comment",
+			dialect: SchemeR7RS(),
+			source:  "#| a #| b |#",
+			expected: []Token{
+				newBlockComment(12, true),
+			},
+		},
+		{
+			name:    "scheme datum comment",
+			dialect: SchemeR7RS(),
+			source:  "#;a b",
+			expected: []Token{
+				newToken(KindDiscard, 2),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+			},
+		},
+		{
+			name:    "scheme quasiquote",
+			dialect: SchemeR7RS(),
+			source:  "`(a ,b ,@c)",
+			expected: []Token{
+				newToken(KindBackquote, 1),
+				newToken(KindOpenParen, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindUnquote, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindUnquoteSplicing, 2),
+				newToken(KindSymbol, 1),
+				newToken(KindCloseParen, 1),
+			},
+		},
+		{
+			name:    "scheme characters",
+			dialect: SchemeR7RS(),
+			source:  `#\a #\space #\(`,
+			expected: []Token{
+				newCharacter(3),
+				newToken(KindWhitespace, 1),
+				newCharacter(7),
+				newToken(KindWhitespace, 1),
+				newCharacter(3),
+			},
+		},
+		{
+			name:    "scheme symbol characters",
+			dialect: SchemeR7RS(),
+			source:  "$x ~y a^b",
+			expected: []Token{
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 3),
+			},
+		},
+		{
+			name:    "brackets as parens",
+			dialect: Dialect{BracketsAsParens: true},
+			source:  "(let ([x 1]) x)",
+			expected: []Token{
+				newToken(KindOpenParen, 1),
+				newToken(KindSymbol, 3),
+				newToken(KindWhitespace, 1),
+				newToken(KindOpenParen, 1),
+				newToken(KindOpenParen, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseParen, 1),
+				newToken(KindCloseParen, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindCloseParen, 1),
+			},
+		},
+		{
+			name:    "common lisp",
+			dialect: CommonLisp(),
+			source:  "[a] #| x |# ,b",
+			expected: []Token{
+				newToken(KindSymbol, 3),
+				newToken(KindWhitespace, 1),
+				newToken(KindBlockComment, 7),
+				newToken(KindWhitespace, 1),
+				newToken(KindUnquote, 1),
+				newToken(KindSymbol, 1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sc sliceConsumer
+			err := Tokenize(&sc, tt.source, WithDialect(tt.dialect))
+
+			var diags Diagnostics
+			if err != nil && !errors.As(err, &diags) {
+				t.Fatal(err)
+			}
+			diffTokens(t, tt.source, tt.expected, sc.Tokens())
+
+			tokenizer := NewTokenizer(strings.NewReader(tt.source), WithDialect(tt.dialect))
+			var received []Token
+			for token := range tokenizer.All() {
+				received = append(received, token)
+			}
+			diffTokens(t, tt.source, tt.expected, received)
+		})
+	}
+}
+
+func TestDialectPresets(t *testing.T) {
+	d := Clojure()
+	d.Comma = CommaToken
+	d.BlockComments = true
+	requireEqual(t, Clojure(), Dialect{Comma: CommaWhitespace})
+
+	var sc sliceConsumer
+	if err := Tokenize(&sc, "a,b"); err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, KindWhitespace, sc.Tokens()[1].Kind())
+}
+
+func TestBooleanValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{"#t", true},
+		{"#true", true},
+		{"#f", false},
+		{"#false", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			var sc sliceConsumer
+			if err := Tokenize(&sc, tt.source, WithDialect(SchemeR7RS())); err != nil {
+				t.Fatal(err)
+			}
+			v, err := sc.Tokens()[0].Literal().Boolean().Value(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, v)
+		})
+	}
+}
+
+func TestHashCharacterValue(t *testing.T) {
+	for source, expected := range map[string]rune{`#\a`: 'a', `#\space`: ' ', `\A`: 'A'} {
+		var sc sliceConsumer
+		if err := Tokenize(&sc, source, WithDialect(SchemeR7RS())); err != nil {
+			t.Fatal(err)
+		}
+		v, err := sc.Tokens()[0].Literal().Character().Value(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requireEqual(t, expected, v)
+	}
+}
+
+func newBoolean(tlen int) Token {
+	return Token{
+		kind: KindLiteral,
+		len:  uint32(tlen),
+		literal: Literal{
+			kind: LiteralKindBoolean,
+		},
+	}
+}
+
+func newBlockComment(tlen int, unterminated bool) Token {
+	var flags commentFlags
+	flags.setIf(unterminated, commentFlagUnterminated)
+	return Token{
+		kind:    KindBlockComment,
+		len:     uint32(tlen),
+		comment: flags,
+	}
+}
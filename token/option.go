@@ -0,0 +1,34 @@
+package token
+
+type Option func(*options)
+
+type options struct {
+	failFast    bool
+	concurrency int
+	dialect     Dialect
+	ascii       asciiTable
+	validators  []Validator
+}
+
+type Validator func(tok Token, text string) Diagnostics
+
+func FailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+func Validate(v Validator) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, v)
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{dialect: Clojure()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.ascii = newASCIITable(&o.dialect)
+	return o
+}
@@ -0,0 +1,183 @@
+package token
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const minChunkSize = 64 << 10
+
+func Concurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+func TokenizeParallel(consumer TokenConsumer, s string, opts ...Option) error {
+	o := newOptions(opts)
+	n := o.concurrency
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	if n == 1 || len(s) < 2*minChunkSize {
+		return Tokenize(consumer, s, opts...)
+	}
+	return tokenizeChunks(consumer, s, max(len(s)/n, minChunkSize), opts)
+}
+
+type chunk struct {
+	start   int
+	end     int
+	buf     TokenBuffer
+	diags   Diagnostics
+	stopped bool
+}
+
+func (c *chunk) tokenize(s string, opts []Option) {
+	sc := newScanner(s[c.start:c.end], opts)
+	for {
+		token, ok := sc.next()
+		if !ok {
+			break
+		}
+		c.buf.append(token)
+	}
+	c.diags, c.stopped = sc.diags, sc.stopped
+}
+
+func (c *chunk) aligned(s string, o *options) bool {
+	if c.stopped {
+		return false
+	}
+	split := c.end - c.start
+	i := c.buf.Len()
+	for i > 0 && c.buf.End(i-1) > split-relexLookback {
+		i--
+	}
+	if i == c.buf.Len() {
+		return true
+	}
+
+	pos := c.start + c.buf.Start(i)
+	t := &tokenizer{s: s[pos:], eof: true, dialect: &o.dialect, ascii: &o.ascii}
+	for ; i < c.buf.Len(); i++ {
+		if t.Advance() != c.buf.At(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenizeChunks(consumer TokenConsumer, s string, size int, opts []Option) error {
+	o := newOptions(opts)
+	points := splitPoints(s, size, &o.dialect)
+	chunks := make([]chunk, len(points)-1)
+	var wg sync.WaitGroup
+	for i := range chunks {
+		chunks[i].start, chunks[i].end = points[i], points[i+1]
+		wg.Add(1)
+		go func(c *chunk) {
+			defer wg.Done()
+			c.tokenize(s, opts)
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	var diags Diagnostics
+	cursor, located := *NewCursor(), 0
+	for i := 0; i < len(chunks); i++ {
+		c := &chunks[i]
+		if c.stopped {
+			c = &chunk{start: c.start, end: len(s)}
+			c.tokenize(s, opts)
+			i = len(chunks)
+		}
+		for i+1 < len(chunks) && !c.aligned(s, &o) {
+			i++
+			c = &chunk{start: c.start, end: chunks[i].end}
+			c.tokenize(s, opts)
+		}
+
+		if len(c.diags) > 0 {
+			cursor.Advance(s[located:c.start])
+			located = c.start
+			c.diags.shift(cursor.Pos())
+			diags = append(diags, c.diags...)
+		}
+		if buf, ok := consumer.(*TokenBuffer); ok {
+			buf.appendBuffer(&c.buf)
+		} else {
+			for j := range c.buf.Len() {
+				consumer.ConsumeToken(c.buf.At(j))
+			}
+		}
+		if c.stopped {
+			break
+		}
+	}
+	return diags.Err()
+}
+
+func splitPoints(s string, size int, d *Dialect) []int {
+	points := []int{0}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case ';':
+			if j := strings.IndexByte(s[i:], '\n'); j > 0 {
+				i += j - 1
+			} else {
+				i = len(s)
+			}
+		case '\\':
+			i++
+		case '#':
+			if d.BlockComments && i+1 < len(s) && s[i+1] == '|' {
+				i = skipBlockComment(s, i)
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth = max(depth-1, 0)
+		case '\n':
+			if depth == 0 && i+1-points[len(points)-1] >= size && i+1 < len(s) && startsForm(s[i+1]) {
+				points = append(points, i+1)
+			}
+		}
+	}
+	return append(points, len(s))
+}
+
+func skipBlockComment(s string, i int) int {
+	depth := 0
+	for ; i+1 < len(s); i++ {
+		switch s[i : i+2] {
+		case "#|":
+			depth++
+			i++
+		case "|#":
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+func startsForm(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', ',', ')', ']', '}':
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,124 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeParallel(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		opts   []Option
+	}{
+		{
+			name:   "top-level forms",
+			source: "(ns a)\n(def b 1)\n\n(defn c [x]\n  (inc x))\n:d\n",
+		},
+		{
+			name:   "multiline strings",
+			source: "(def a \"x\n(def b 1)\n\")\n(def c \"\\\"\n(d)\")\n",
+		},
+		{
+			name:   "comments",
+			source: "; (\n(a)\n;; \"\n(b)\n",
+		},
+		{
+			name:   "characters",
+			source: "\\(\n(a \\)\n b)\n\\\"\n(c)\n",
+		},
+		{
+			name:   "unterminated string",
+			source: "(a)\n\"b\n(c)\n(d)\n",
+		},
+		{
+			name:   "diagnostics",
+			source: "(a 1e)\n(b \"\\q\")\n  (c 0x)\n\"d\n",
+		},
+		{
+			name:   "fail fast",
+			source: "(a)\n(b 1e)\n(c 0x)\n",
+			opts:   []Option{FailFast()},
+		},
+		{
+			name:   "unbalanced",
+			source: ")\n(a\n(b)\n]\n(c)\n",
+		},
+		{
+			name:   "block comments",
+			source: "#| (\n(a) #| |#\n(b) |#\n(c)\n",
+			opts:   []Option{WithDialect(SchemeR7RS())},
+		},
+		{
+			name:   "tags",
+			source: "#my.ns/tag (a)\n#inst \"b\"\n(#c/d [e])\n",
+		},
+		{
+			name:   "crlf",
+			source: "(a)\r\n(b 1e)\r\n\r\n(c)",
+		},
+		{
+			name:   "empty",
+			source: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected sliceConsumer
+			expectedErr := Tokenize(&expected, tt.source, tt.opts...)
+
+			for size := 1; size <= len(tt.source); size++ {
+				var received sliceConsumer
+				err := tokenizeChunks(&received, tt.source, size, tt.opts)
+				diffTokens(t, tt.source, expected.Tokens(), received.Tokens())
+				requireEqual(t, len(expected.Tokens()), len(received.Tokens()))
+				if !reflect.DeepEqual(expectedErr, err) {
+					t.Fatalf("size %d: expected %v, received %v", size, expectedErr, err)
+				}
+
+				var buf TokenBuffer
+				_ = tokenizeChunks(&buf, tt.source, size, tt.opts)
+				diffTokens(t, tt.source, expected.Tokens(), buf.Tokens())
+				requireEqual(t, len(expected.Tokens()), buf.Len())
+			}
+		})
+	}
+}
+
+func TestSplitPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		dialect  Dialect
+		expected []int
+	}{
+		{
+			name:     "forms",
+			source:   "(a)\n(b)\n  (c)\n(d\n e)\n",
+			dialect:  Clojure(),
+			expected: []int{0, 4, 14, 21},
+		},
+		{
+			name:     "strings and comments",
+			source:   "\"\n(a)\"\n; \"\n(b)\n\\(\n(c)",
+			dialect:  Clojure(),
+			expected: []int{0, 7, 11, 15, 18, 21},
+		},
+		{
+			name:     "block comments",
+			source:   "#| #|\n(a) |#\n(b) |#\n(c)",
+			dialect:  SchemeR7RS(),
+			expected: []int{0, 20, 23},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := splitPoints(tt.source, 1, &tt.dialect)
+			if !reflect.DeepEqual(tt.expected, received) {
+				t.Fatalf("expected %v, received %v", tt.expected, received)
+			}
+		})
+	}
+}
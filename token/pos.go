@@ -0,0 +1,83 @@
+package token
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type Pos struct {
+	Offset      int
+	Line        int
+	Column      int
+	UTF16Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+func (s Span) Len() int {
+	return s.End.Offset - s.Start.Offset
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%v-%v", s.Start, s.End)
+}
+
+const lineTerminators = "\r\n\u2028\u2029"
+
+type Cursor struct {
+	pos Pos
+	cr  bool
+}
+
+func NewCursor() *Cursor {
+	return &Cursor{pos: Pos{Line: 1, Column: 1, UTF16Column: 1}}
+}
+
+func (c *Cursor) Pos() Pos {
+	return c.pos
+}
+
+func (c *Cursor) Advance(s string) Span {
+	start := c.pos
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		c.pos.Offset += size
+
+		switch r {
+		case '\n':
+			if !c.cr {
+				c.newLine()
+			}
+			c.cr = false
+			continue
+		case '\r', '\u2028', '\u2029':
+			c.newLine()
+			c.cr = r == '\r'
+			continue
+		}
+
+		c.cr = false
+		c.pos.Column++
+		if n := utf16.RuneLen(r); n > 0 {
+			c.pos.UTF16Column += n
+		} else {
+			c.pos.UTF16Column++
+		}
+	}
+	return Span{Start: start, End: c.pos}
+}
+
+func (c *Cursor) newLine() {
+	c.pos.Line++
+	c.pos.Column = 1
+	c.pos.UTF16Column = 1
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		expected Pos
+	}{
+		{"empty", nil, Pos{Offset: 0, Line: 1, Column: 1, UTF16Column: 1}},
+		{"ascii", []string{"abc"}, Pos{Offset: 3, Line: 1, Column: 4, UTF16Column: 4}},
+		{"line feed", []string{"ab\ncd"}, Pos{Offset: 5, Line: 2, Column: 3, UTF16Column: 3}},
+		{"carriage return", []string{"ab\rcd"}, Pos{Offset: 5, Line: 2, Column: 3, UTF16Column: 3}},
+		{"crlf", []string{"ab\r\ncd"}, Pos{Offset: 6, Line: 2, Column: 3, UTF16Column: 3}},
+		{"split crlf", []string{"ab\r", "\ncd"}, Pos{Offset: 6, Line: 2, Column: 3, UTF16Column: 3}},
+		{"lfcr", []string{"\n\r"}, Pos{Offset: 2, Line: 3, Column: 1, UTF16Column: 1}},
+		{"double crlf", []string{"\r\n\r\n"}, Pos{Offset: 4, Line: 3, Column: 1, UTF16Column: 1}},
+		{"next line is not a break", []string{"a\u0085b"}, Pos{Offset: 4, Line: 1, Column: 4, UTF16Column: 4}},
+		{"line separator", []string{"a\u2028b"}, Pos{Offset: 5, Line: 2, Column: 2, UTF16Column: 2}},
+		{"paragraph separator", []string{"a\u2029b"}, Pos{Offset: 5, Line: 2, Column: 2, UTF16Column: 2}},
+		{"multi-byte", []string{"häx"}, Pos{Offset: 4, Line: 1, Column: 4, UTF16Column: 4}},
+		{"astral", []string{"a😀b"}, Pos{Offset: 6, Line: 1, Column: 4, UTF16Column: 5}},
+		{"invalid utf-8", []string{"a\xffb"}, Pos{Offset: 3, Line: 1, Column: 4, UTF16Column: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCursor()
+			for _, chunk := range tt.chunks {
+				c.Advance(chunk)
+			}
+			requireEqual(t, tt.expected, c.Pos())
+		})
+	}
+}
+
+func TestTokenizerSpans(t *testing.T) {
+	source := "(foo\r\n  \"bär\"  ; 😀\n  \\a)"
+	expected := []string{
+		"1:1-1:2",
+		"1:2-1:5",
+		"1:5-2:3",
+		"2:3-2:8",
+		"2:8-3:2",
+		"3:2-3:5",
+		"3:5-4:3",
+		"4:3-4:5",
+		"4:5-4:6",
+	}
+
+	tokenizer := NewTokenizer(iotest.OneByteReader(strings.NewReader(source)))
+	var received []string
+	offset := 0
+	for token, span := range tokenizer.Spanned() {
+		requireEqual(t, offset, span.Start.Offset)
+		requireEqual(t, token.Len(), span.Len())
+		offset = span.End.Offset
+		received = append(received, span.String())
+	}
+	if err := tokenizer.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	requireEqual(t, fmt.Sprint(expected), fmt.Sprint(received))
+	requireEqual(t, len(source), offset)
+	requireEqual(t, "4:5-4:6", tokenizer.Span().String())
+}
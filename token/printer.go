@@ -0,0 +1,145 @@
+package token
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type PrintMode uint8
+
+const (
+	PrintExact PrintMode = iota
+	PrintNormalized
+	PrintDebug
+)
+
+type Printer struct {
+	w      io.Writer
+	src    string
+	mode   PrintMode
+	offset int
+	cursor Cursor
+	depth  int
+	space  string
+	wrote  bool
+	opened bool
+	err    error
+}
+
+func NewPrinter(w io.Writer, src string, mode PrintMode) *Printer {
+	return &Printer{w: w, src: src, mode: mode, cursor: *NewCursor()}
+}
+
+func (p *Printer) Print(tokens []Token) error {
+	for _, t := range tokens {
+		p.ConsumeToken(t)
+	}
+	return p.Flush()
+}
+
+func (p *Printer) ConsumeToken(t Token) {
+	text := p.src[p.offset : p.offset+int(t.len)]
+	p.offset += int(t.len)
+	switch p.mode {
+	case PrintExact:
+		p.write(text)
+	case PrintNormalized:
+		p.normalized(t, text)
+	case PrintDebug:
+		p.debug(t, text)
+	default:
+		panic(fmt.Errorf("unknown print mode: %d", p.mode))
+	}
+}
+
+func (p *Printer) Flush() error {
+	if p.mode == PrintNormalized && p.wrote && lineBreaks(p.space) > 0 {
+		p.write("\n")
+	}
+	p.space = ""
+	return p.err
+}
+
+func (p *Printer) normalized(t Token, text string) {
+	if t.kind == KindWhitespace {
+		p.space = text
+		return
+	}
+
+	closer := isCloser(t.kind)
+	if closer && p.depth > 0 {
+		p.depth--
+	}
+	if n := lineBreaks(p.space); n > 0 && p.wrote {
+		p.write(strings.Repeat("\n", min(n, 2)) + strings.Repeat("  ", p.depth))
+	} else if p.space != "" && p.wrote && !p.opened && !closer {
+		p.write(" ")
+	}
+	p.space = ""
+
+	if t.kind == KindLineComment {
+		text = strings.TrimRight(text, " \t")
+	}
+	p.write(text)
+	p.opened = isOpener(t.kind)
+	if p.opened {
+		p.depth++
+	}
+}
+
+func (p *Printer) debug(t Token, text string) {
+	span := p.cursor.Advance(text)
+	switch t.kind {
+	case KindSymbol:
+		p.printf("%v\t%+v\t%+v\t%q\n", span, t, t.Symbol(), text)
+	case KindKeyword:
+		p.printf("%v\t%+v\t%+v\t%q\n", span, t, t.Keyword(), text)
+	case KindTag:
+		p.printf("%v\t%+v\t%+v\t%q\n", span, t, t.Tag(), text)
+	case KindBlockComment:
+		p.printf("%v\t%+v\t%+v\t%q\n", span, t, t.BlockComment(), text)
+	default:
+		p.printf("%v\t%+v\t%q\n", span, t, text)
+	}
+}
+
+func (p *Printer) write(s string) {
+	if p.err != nil {
+		return
+	}
+	p.wrote = true
+	_, p.err = io.WriteString(p.w, s)
+}
+
+func (p *Printer) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	p.wrote = true
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func lineBreaks(s string) int {
+	c := NewCursor()
+	c.Advance(s)
+	return c.Pos().Line - 1
+}
+
+func isOpener(k Kind) bool {
+	switch k {
+	case KindOpenParen, KindOpenBracket, KindOpenBrace, KindOpenSet, KindOpenFn:
+		return true
+	default:
+		return false
+	}
+}
+
+func isCloser(k Kind) bool {
+	switch k {
+	case KindCloseParen, KindCloseBracket, KindCloseBrace:
+		return true
+	default:
+		return false
+	}
+}
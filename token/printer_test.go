@@ -0,0 +1,126 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     PrintMode
+		source   string
+		expected string
+	}{
+		{
+			name:     "exact",
+			mode:     PrintExact,
+			source:   "  (a ,b)\t; c  \n\n\n[\"d\"  ]",
+			expected: "  (a ,b)\t; c  \n\n\n[\"d\"  ]",
+		},
+		{
+			name:     "normalized spaces",
+			mode:     PrintNormalized,
+			source:   "(  a ,\tb  )  [ \"d  e\"  ]",
+			expected: "(a b) [\"d  e\"]",
+		},
+		{
+			name:     "normalized lines",
+			mode:     PrintNormalized,
+			source:   "\n\n(defn f [x]   \n        (inc x)\n\n\n\n)   ; done  \n\n",
+			expected: "(defn f [x]\n  (inc x)\n\n) ; done\n",
+		},
+		{
+			name:     "normalized nesting",
+			mode:     PrintNormalized,
+			source:   "{:a\n#{1\n2}\n:b #(f\n%)}",
+			expected: "{:a\n  #{1\n    2}\n  :b #(f\n    %)}",
+		},
+		{
+			name:     "normalized unbalanced",
+			mode:     PrintNormalized,
+			source:   ")\n(a",
+			expected: ")\n(a",
+		},
+		{
+			name:   "debug",
+			mode:   PrintDebug,
+			source: "(a/b :c\n#d/e 1)",
+			expected: "1:1-1:2\ttoken.Token{Kind:open-paren Len:1}\t\"(\"\n" +
+				"1:2-1:5\ttoken.Token{Kind:symbol Len:3}\ttoken.Symbol{HasNamespace:true NamespaceLen:1}\t\"a/b\"\n" +
+				"1:5-1:6\ttoken.Token{Kind:whitespace Len:1}\t\" \"\n" +
+				"1:6-1:8\ttoken.Token{Kind:keyword Len:2}\ttoken.Keyword{AutoResolved:false HasNamespace:false NamespaceLen:0}\t\":c\"\n" +
+				"1:8-2:1\ttoken.Token{Kind:whitespace Len:1}\t\"\\n\"\n" +
+				"2:1-2:5\ttoken.Token{Kind:tag Len:4}\ttoken.Tag{HasNamespace:true NamespaceLen:1}\t\"#d/e\"\n" +
+				"2:5-2:6\ttoken.Token{Kind:whitespace Len:1}\t\" \"\n" +
+				"2:6-2:7\ttoken.Token{Kind:token.Literal{token.Integer{Base:10 EmptyInt:false BigInt:false InvalidRadix:false}} Len:1}\t\"1\"\n" +
+				"2:7-2:8\ttoken.Token{Kind:close-paren Len:1}\t\")\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sc sliceConsumer
+			if err := Tokenize(&sc, tt.source); err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			if err := NewPrinter(&b, tt.source, tt.mode).Print(sc.Tokens()); err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, b.String())
+
+			b.Reset()
+			p := NewPrinter(&b, tt.source, tt.mode)
+			if err := Tokenize(p, tt.source); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, b.String())
+		})
+	}
+}
+
+func TestPrinterNormalizedIdempotent(t *testing.T) {
+	for name, source := range loadCorpus(t) {
+		t.Run(name, func(t *testing.T) {
+			once := printNormalized(t, source)
+			requireEqual(t, once, printNormalized(t, once))
+		})
+	}
+}
+
+func TestPrinterError(t *testing.T) {
+	source := "(a b)"
+	var sc sliceConsumer
+	if err := Tokenize(&sc, source); err != nil {
+		t.Fatal(err)
+	}
+	err := NewPrinter(failingWriter{}, source, PrintExact).Print(sc.Tokens())
+	requireEqual(t, errWrite, err)
+}
+
+func printNormalized(tb testing.TB, source string) string {
+	tb.Helper()
+	var sc sliceConsumer
+	if err := Tokenize(&sc, source); err != nil {
+		tb.Fatal(err)
+	}
+	var b strings.Builder
+	if err := NewPrinter(&b, source, PrintNormalized).Print(sc.Tokens()); err != nil {
+		tb.Fatal(err)
+	}
+	return b.String()
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const relexLookback = 2 * utf8.UTFMax
+
+type Edit struct {
+	Offset int
+	Delete int
+	Insert string
+}
+
+func (e Edit) Apply(src string) string {
+	return src[:e.Offset] + e.Insert + src[e.Offset+e.Delete:]
+}
+
+type Change struct {
+	Start  int
+	OldEnd int
+	Tokens []Token
+}
+
+func (c Change) Apply(tokens []Token) []Token {
+	result := make([]Token, 0, len(tokens)-(c.OldEnd-c.Start)+len(c.Tokens))
+	result = append(result, tokens[:c.Start]...)
+	result = append(result, c.Tokens...)
+	return append(result, tokens[c.OldEnd:]...)
+}
+
+func (c Change) NewEnd() int {
+	return c.Start + len(c.Tokens)
+}
+
+func Relex(tokens []Token, src string, edit Edit, opts ...Option) Change {
+	o := newOptions(opts)
+	oldEditEnd := edit.Offset + edit.Delete
+	newEditEnd := edit.Offset + len(edit.Insert)
+	delta := newEditEnd - oldEditEnd
+	if edit.Offset < 0 || edit.Delete < 0 || newEditEnd > len(src) {
+		panic(fmt.Errorf("edit %+v out of range", edit))
+	}
+
+	i, restart := 0, 0
+	for i < len(tokens) && restart+int(tokens[i].len) <= edit.Offset-relexLookback {
+		restart += int(tokens[i].len)
+		i++
+	}
+
+	c := Change{Start: i}
+	j, oldStart := i, restart
+	t := &tokenizer{s: src[restart:], eof: true, dialect: &o.dialect, ascii: &o.ascii}
+	for pos := restart; ; {
+		for j < len(tokens) && (oldStart < oldEditEnd || oldStart+delta < pos) {
+			oldStart += int(tokens[j].len)
+			j++
+		}
+		if j < len(tokens) && pos >= newEditEnd && oldStart+delta == pos {
+			c.OldEnd = j
+			return c
+		}
+
+		token := t.Advance()
+		if token.Kind() == kindNone {
+			c.OldEnd = len(tokens)
+			return c
+		}
+		c.Tokens = append(c.Tokens, token)
+		pos += int(token.len)
+	}
+}
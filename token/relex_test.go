@@ -0,0 +1,128 @@
+package token
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRelex(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		edit     Edit
+		start    int
+		oldEnd   int
+		newCount int
+	}{
+		{
+			name:     "rename symbol",
+			source:   "(defn foo [a b] (+ a b))",
+			edit:     Edit{Offset: 17, Delete: 1, Insert: "-"},
+			start:    4,
+			oldEnd:   13,
+			newCount: 9,
+		},
+		{
+			name:     "extend symbol",
+			source:   "(foo bar baz qux quux corge)",
+			edit:     Edit{Offset: 12, Insert: "z"},
+			start:    2,
+			oldEnd:   6,
+			newCount: 4,
+		},
+		{
+			name:     "open string swallows rest",
+			source:   "(a b) (c d) (e f)",
+			edit:     Edit{Offset: 6, Insert: `"`},
+			start:    0,
+			oldEnd:   17,
+			newCount: 7,
+		},
+		{
+			name:     "close string",
+			source:   `(a "b c d e f g h)`,
+			edit:     Edit{Offset: 5, Insert: `"`},
+			start:    0,
+			oldEnd:   4,
+			newCount: 17,
+		},
+		{
+			name:     "comment out",
+			source:   "(a)\n(b)\n(c)\n(d)\n(e)",
+			edit:     Edit{Offset: 8, Insert: ";"},
+			start:    0,
+			oldEnd:   11,
+			newCount: 9,
+		},
+		{
+			name:     "delete everything",
+			source:   "(a b c)",
+			edit:     Edit{Offset: 0, Delete: 7},
+			start:    0,
+			oldEnd:   7,
+			newCount: 0,
+		},
+		{
+			name:     "insert into empty",
+			source:   "",
+			edit:     Edit{Offset: 0, Insert: "(a)"},
+			start:    0,
+			oldEnd:   0,
+			newCount: 3,
+		},
+		{
+			name:     "append",
+			source:   "(a)",
+			edit:     Edit{Offset: 3, Insert: " b"},
+			start:    0,
+			oldEnd:   3,
+			newCount: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := tokenizeAll(t, tt.source)
+			src := tt.edit.Apply(tt.source)
+
+			c := Relex(old, src, tt.edit)
+			diffTokens(t, src, tokenizeAll(t, src), c.Apply(old))
+			requireEqual(t, tt.start, c.Start)
+			requireEqual(t, tt.oldEnd, c.OldEnd)
+			requireEqual(t, tt.newCount, len(c.Tokens))
+			requireEqual(t, tt.start+tt.newCount, c.NewEnd())
+		})
+	}
+}
+
+func TestRelexRandom(t *testing.T) {
+	sources := []string{
+		`(defn greet [name] (str "hello, " name "!\n"))`,
+		"; comment\n(let [x 1/2 y 0x1F z #\"\\d+\"]\n  #{:a ::b/c \\newline})",
+		"#?(:clj (Math/abs -1.5e10M) :cljs (js/Math.abs 42N)) #_ignored @state `(~@xs ~y)",
+	}
+	fragments := []string{"", " ", "\"", "\\", ";", "\n", "(", ")", "#", "1", "e", "x/y", ":", "#_", "é", "日本"}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, source := range sources {
+		tokens := tokenizeAll(t, source)
+		for i := 0; i < 500; i++ {
+			offset := rng.Intn(len(source) + 1)
+			del := rng.Intn(min(4, len(source)-offset) + 1)
+			edit := Edit{Offset: offset, Delete: del, Insert: fragments[rng.Intn(len(fragments))]}
+			src := edit.Apply(source)
+
+			c := Relex(tokens, src, edit)
+			diffTokens(t, src, tokenizeAll(t, src), c.Apply(tokens))
+
+			source, tokens = src, c.Apply(tokens)
+		}
+	}
+}
+
+func tokenizeAll(tb testing.TB, source string) []Token {
+	tb.Helper()
+	var sc sliceConsumer
+	_ = Tokenize(&sc, source)
+	return sc.Tokens()
+}
@@ -0,0 +1,135 @@
+package token
+
+import (
+	"io"
+	"iter"
+)
+
+const (
+	minReadSize              = 4096
+	maxConsecutiveEmptyReads = 100
+)
+
+type Tokenizer struct {
+	r      io.Reader
+	s      string
+	buf    []byte
+	err    error
+	cursor *Cursor
+	span   Span
+	text   string
+	opts   options
+	diags  Diagnostics
+}
+
+func NewTokenizer(r io.Reader, opts ...Option) *Tokenizer {
+	return &Tokenizer{r: r, cursor: NewCursor(), opts: newOptions(opts)}
+}
+
+func (t *Tokenizer) Next() (Token, error) {
+	for {
+		if t.err != nil && t.err != io.EOF {
+			return Token{}, t.err
+		}
+
+		eof := t.err == io.EOF
+		if len(t.s) == 0 && eof {
+			return Token{}, io.EOF
+		}
+
+		if len(t.s) > 0 {
+			tok := tokenizer{s: t.s, eof: eof, dialect: &t.opts.dialect, ascii: &t.opts.ascii}
+			token := tok.Advance()
+			if !tok.short {
+				text := t.s[:token.len]
+				if found := t.opts.diagnose(token, text); len(found) > 0 {
+					found.locate(*t.cursor, text)
+					t.diags = append(t.diags, found...)
+					if t.opts.failFast && found.Err() != nil {
+						t.err = found
+						return Token{}, t.err
+					}
+				}
+				t.text = text
+				t.span = t.cursor.Advance(t.text)
+				t.s = t.s[token.len:]
+				return token, nil
+			}
+		}
+
+		t.fill()
+	}
+}
+
+func (t *Tokenizer) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			token, err := t.Next()
+			if err != nil {
+				return
+			}
+			if !yield(token) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Tokenizer) Spanned() iter.Seq2[Token, Span] {
+	return func(yield func(Token, Span) bool) {
+		for {
+			token, err := t.Next()
+			if err != nil {
+				return
+			}
+			if !yield(token, t.span) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Tokenizer) Span() Span {
+	return t.span
+}
+
+func (t *Tokenizer) Pos() Pos {
+	return t.cursor.Pos()
+}
+
+func (t *Tokenizer) Text() string {
+	return t.text
+}
+
+func (t *Tokenizer) Diagnostics() Diagnostics {
+	return t.diags
+}
+
+func (t *Tokenizer) Err() error {
+	if t.err != io.EOF {
+		return t.err
+	}
+	if len(t.s) > 0 {
+		return nil
+	}
+	return t.diags.Err()
+}
+
+func (t *Tokenizer) fill() {
+	n := max(minReadSize, len(t.s))
+	if cap(t.buf) < n {
+		t.buf = make([]byte, n)
+	}
+	for i := 0; i < maxConsecutiveEmptyReads; i++ {
+		read, err := t.r.Read(t.buf[:n])
+		t.s += string(t.buf[:read])
+		if err != nil {
+			t.err = err
+			return
+		}
+		if read > 0 {
+			return
+		}
+	}
+	t.err = io.ErrNoProgress
+}
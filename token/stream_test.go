@@ -0,0 +1,141 @@
+package token
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTokenizer(t *testing.T) {
+	sources := []struct {
+		name   string
+		source string
+	}{
+		{"empty", ""},
+		{"basic literals", `(literal 123 123.456 "foo" \q)`},
+		{"whitespace runs", "  \t\n    (a)\u0085  "},
+		{"multi-byte symbols", ":l33T_häxÖrZ: äöå/ö 日本語"},
+		{"multi-byte string", `"hälló wörld 日本語"`},
+		{"multi-byte character", `\ä \日 ሴ`},
+		{"dotted decimal at boundary", "0. 0.0.o 12.3E10 98_76_._54e+592"},
+		{"unterminated string", `(foo "bar`},
+		{"string escapes", `"\u00e9\377\q\u12\""`},
+		{"missing character", `\`},
+		{"line comments", "; comment\n(a ; b\n c) ;"},
+		{"unquote splicing", "`(~@a ~b)"},
+		{"dispatch macros", `#{1} #(%) #'a #_b #?(:a 1) #?@(:b 2) ##Inf #::{} #inst "x" #"\d\"" #`},
+	}
+
+	readers := []struct {
+		name string
+		fn   func(io.Reader) io.Reader
+	}{
+		{"whole", func(r io.Reader) io.Reader { return r }},
+		{"one byte", iotest.OneByteReader},
+		{"half", iotest.HalfReader},
+		{"data err", iotest.DataErrReader},
+	}
+
+	for _, src := range sources {
+		for _, rd := range readers {
+			t.Run(src.name+"/"+rd.name, func(t *testing.T) {
+				var sc sliceConsumer
+				var diags Diagnostics
+				expectedErr := Tokenize(&sc, src.source)
+				if expectedErr != nil && !errors.As(expectedErr, &diags) {
+					t.Fatal(expectedErr)
+				}
+
+				tokenizer := NewTokenizer(rd.fn(strings.NewReader(src.source)))
+				var received []Token
+				for {
+					token, err := tokenizer.Next()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					received = append(received, token)
+				}
+
+				diffTokens(t, src.source, sc.Tokens(), received)
+				if !reflect.DeepEqual(diags, tokenizer.Diagnostics()) {
+					t.Fatalf("expected diagnostics %v, received %v", diags, tokenizer.Diagnostics())
+				}
+				if !reflect.DeepEqual(expectedErr, tokenizer.Err()) {
+					t.Fatalf("expected %v, received %v", expectedErr, tokenizer.Err())
+				}
+			})
+		}
+	}
+}
+
+func TestTokenizerAll(t *testing.T) {
+	source := `(foo "bar" \baz 1.5)`
+
+	var sc sliceConsumer
+	if err := Tokenize(&sc, source); err != nil {
+		t.Fatal(err)
+	}
+
+	tokenizer := NewTokenizer(iotest.OneByteReader(strings.NewReader(source)))
+	var received []Token
+	var text strings.Builder
+	for token := range tokenizer.All() {
+		received = append(received, token)
+		text.WriteString(tokenizer.Text())
+	}
+	if err := tokenizer.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	diffTokens(t, source, sc.Tokens(), received)
+	requireEqual(t, source, text.String())
+}
+
+func TestTokenizerAllBreak(t *testing.T) {
+	tokenizer := NewTokenizer(strings.NewReader("(a b c)"))
+	for token := range tokenizer.All() {
+		requireEqual(t, KindOpenParen, token.Kind())
+		break
+	}
+
+	token, err := tokenizer.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, KindSymbol, token.Kind())
+}
+
+func TestTokenizerErrors(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	t.Run("read error", func(t *testing.T) {
+		tokenizer := NewTokenizer(io.MultiReader(strings.NewReader("(a "), iotest.ErrReader(errBroken)))
+		var received []Token
+		for token := range tokenizer.All() {
+			received = append(received, token)
+		}
+		if err := tokenizer.Err(); !errors.Is(err, errBroken) {
+			t.Fatalf("expected %v, received %v", errBroken, err)
+		}
+		diffTokens(t, "(a ", []Token{newToken(KindOpenParen, 1), newToken(KindSymbol, 1)}, received)
+	})
+
+	t.Run("no progress", func(t *testing.T) {
+		tokenizer := NewTokenizer(emptyReader{})
+		if _, err := tokenizer.Next(); !errors.Is(err, io.ErrNoProgress) {
+			t.Fatalf("expected %v, received %v", io.ErrNoProgress, err)
+		}
+	})
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,126 @@
+(ns service.http
+  (:require [clojure.string :as str]
+            [clojure.walk :refer [keywordize-keys]]))
+
+;; ---------------------------------------------------------------------------
+;; Routing
+
+(def routes
+  [["/health"        {:get  ::health}]
+   ["/users"         {:get  ::list-users
+                      :post ::create-user}]
+   ["/users/:id"     {:get    ::get-user
+                      :put    ::update-user
+                      :delete ::delete-user}]
+   ["/files/*path"   {:get  ::serve-file}]])
+
+(defn- segments [path]
+  (remove str/blank? (str/split path #"/")))
+
+(defn- match-segment [pattern actual]
+  (cond
+    (str/starts-with? pattern ":") [(keyword (subs pattern 1)) actual]
+    (= pattern actual)             []
+    :else                          nil))
+
+(defn match-route
+  "Returns [handler-key params] for the request, or nil when nothing matches."
+  [{:keys [uri request-method]}]
+  (some (fn [[pattern methods]]
+          (let [expected (segments pattern)
+                actual   (segments uri)]
+            (when-let [handler (get methods request-method)]
+              (if (some #(str/starts-with? % "*") expected)
+                [handler {:path (str/join "/" (drop (dec (count expected)) actual))}]
+                (when (= (count expected) (count actual))
+                  (let [pairs (map match-segment expected actual)]
+                    (when (every? some? pairs)
+                      [handler (into {} (map vec) (filter seq pairs))])))))))
+        routes))
+
+;; ---------------------------------------------------------------------------
+;; Responses
+
+(defn response
+  ([body] (response 200 body))
+  ([status body]
+   {:status  status
+    :headers {"Content-Type" "application/json; charset=utf-8"}
+    :body    body}))
+
+(def not-found (response 404 {:error "not found"}))
+
+(defn- escape-json [s]
+  (-> s
+      (str/replace "\\" "\\\\")
+      (str/replace "\"" "\\\"")
+      (str/replace "\n" "\\n")
+      (str/replace "\t" "\\t")))
+
+(defn to-json
+  [x]
+  (cond
+    (nil? x)     "null"
+    (string? x)  (str \" (escape-json x) \")
+    (keyword? x) (to-json (name x))
+    (number? x)  (str x)
+    (boolean? x) (str x)
+    (map? x)     (str "{" (str/join "," (for [[k v] x] (str (to-json k) ":" (to-json v)))) "}")
+    (coll? x)    (str "[" (str/join "," (map to-json x)) "]")
+    :else        (to-json (str x))))
+
+;; ---------------------------------------------------------------------------
+;; Handlers
+
+(defonce ^:private db (atom {1 {:id 1 :name "Ada"} 2 {:id 2 :name "Grace"}}))
+
+(defmulti handle (fn [handler-key _request _params] handler-key))
+
+(defmethod handle ::health [_ _ _]
+  (response {:ok true :users (count @db)}))
+
+(defmethod handle ::list-users [_ {:keys [query-params]} _]
+  (let [{:strs [limit offset] :or {limit "20" offset "0"}} query-params]
+    (response (->> (vals @db)
+                   (sort-by :id)
+                   (drop (parse-long offset))
+                   (take (parse-long limit))))))
+
+(defmethod handle ::get-user [_ _ {:keys [id]}]
+  (if-let [user (get @db (parse-long id))]
+    (response user)
+    not-found))
+
+(defmethod handle ::create-user [_ {:keys [body]} _]
+  (let [user (keywordize-keys body)
+        id   (inc (apply max 0 (keys @db)))]
+    (swap! db assoc id (assoc user :id id))
+    (response 201 (get @db id))))
+
+(defmethod handle ::delete-user [_ _ {:keys [id]}]
+  (swap! db dissoc (parse-long id))
+  (response 204 nil))
+
+(defmethod handle :default [_ _ _]
+  not-found)
+
+(defn app
+  [request]
+  (if-let [[handler params] (match-route request)]
+    (try
+      (update (handle handler request params) :body to-json)
+      (catch Exception e
+        (response 500 (to-json {:error (ex-message e)}))))
+    (update not-found :body to-json)))
+
+(defn wrap-logging
+  [handler log]
+  (fn [request]
+    (let [start    (System/nanoTime)
+          response (handler request)]
+      (log (format "%s %s -> %d (%.2fms)"
+                   (str/upper-case (name (:request-method request)))
+                   (:uri request)
+                   (:status response)
+                   (/ (- (System/nanoTime) start) 1e6)))
+      response)))
@@ -0,0 +1,116 @@
+(ns inventory.core
+  "Tracks stock levels across warehouses and produces restock orders."
+  (:require [clojure.string :as str]
+            [clojure.set :as set]
+            [clojure.edn :as edn])
+  (:import (java.time Instant Duration)
+           (java.util UUID)))
+
+(set! *warn-on-reflection* true)
+
+(def ^:const default-threshold 10)
+
+(def ^:private warehouses
+  {:north {:id #uuid "8f14e45f-ceea-467f-a0e4-b2f0b0b0a001" :capacity 12000}
+   :south {:id #uuid "8f14e45f-ceea-467f-a0e4-b2f0b0b0a002" :capacity 8000}
+   :east  {:id #uuid "8f14e45f-ceea-467f-a0e4-b2f0b0b0a003" :capacity 4500}})
+
+(defrecord Item [sku name quantity unit-price])
+
+(def sample-item
+  #inventory.core/item {:sku "A-1" :name "Anvil" :quantity 3 :unit-price 12.5M})
+
+(defn ->item
+  "Builds an item from a raw map, coercing numeric fields."
+  [{:keys [sku name quantity unit-price] :or {quantity 0}}]
+  (->Item (str/upper-case sku)
+          (str/trim name)
+          (long quantity)
+          (bigdec unit-price)))
+
+(defn parse-line
+  [line]
+  (let [[sku name qty price] (str/split line #",\s*")]
+    (when (and sku name)
+      (->item {:sku sku
+               :name name
+               :quantity (Long/parseLong qty)
+               :unit-price (BigDecimal. ^String price)}))))
+
+(defn load-items
+  "Reads a CSV export, skipping the header and blank lines."
+  [text]
+  (->> (str/split-lines text)
+       rest
+       (remove str/blank?)
+       (keep parse-line)
+       (into [])))
+
+(defn low-stock?
+  ([item] (low-stock? item default-threshold))
+  ([{:keys [quantity]} threshold]
+   (< quantity threshold)))
+
+(defn restock-order
+  [items & {:keys [threshold multiplier]
+            :or   {threshold default-threshold
+                   multiplier 3}}]
+  (for [item items
+        :when (low-stock? item threshold)
+        :let [needed (- (* threshold multiplier) (:quantity item))]]
+    {:sku      (:sku item)
+     :order    needed
+     :estimate (* needed (:unit-price item))
+     :placed   (Instant/now)}))
+
+(defn total-value
+  [items]
+  (transduce (map #(* (:quantity %) (:unit-price %))) + 0M items))
+
+(defmulti allocate (fn [strategy _items _warehouses] strategy))
+
+(defmethod allocate :round-robin
+  [_ items warehouses]
+  (zipmap (map :sku items) (cycle (keys warehouses))))
+
+(defmethod allocate :by-capacity
+  [_ items warehouses]
+  (let [ranked (sort-by (comp - :capacity val) warehouses)]
+    (into {}
+          (map-indexed (fn [i item]
+                         [(:sku item) (key (nth ranked (mod i (count ranked))))]))
+          items)))
+
+(defmethod allocate :default
+  [strategy & _]
+  (throw (ex-info "Unknown allocation strategy" {:strategy strategy})))
+
+(comment
+  (def sample "sku,name,quantity,price\nA-1, Widget, 4, 2.50\nB-2, Gadget, 40, 11.00\n")
+  (-> sample load-items (restock-order :threshold 5))
+  (total-value (load-items sample))
+  #_(allocate :by-capacity (load-items sample) warehouses))
+
+(defn summarize
+  [items]
+  (let [by-prefix (group-by #(subs (:sku %) 0 1) items)]
+    (reduce-kv (fn [acc prefix group]
+                 (assoc acc (keyword prefix)
+                        {:count (count group)
+                         :units (reduce + (map :quantity group))
+                         :ratio (/ (count group) (max 1 (count items)))}))
+               (sorted-map)
+               by-prefix)))
+
+(defn elapsed-ms
+  ^long [^Instant start]
+  (.toMillis (Duration/between start (Instant/now))))
+
+(defn report
+  [items]
+  (let [start (Instant/now)
+        summary (summarize items)]
+    (doseq [[k {:keys [count units]}] summary]
+      (printf "%-4s %6d %8d%n" (name k) count units))
+    (println (str "generated in " (elapsed-ms start) "ms; id=" (UUID/randomUUID)))
+    summary))
@@ -0,0 +1,84 @@
+(ns util.macros
+  "Assorted macros and reader-heavy code."
+  #?(:clj  (:require [clojure.core.async :as a :refer [go <! >!]])
+     :cljs (:require [cljs.core.async :as a :refer [<! >!]]
+                     [cljs.core.async.macros :refer [go]])))
+
+(defmacro with-timing
+  "Evaluates body, returning [result elapsed-nanos]."
+  [& body]
+  `(let [start# (System/nanoTime)
+         result# (do ~@body)]
+     [result# (- (System/nanoTime) start#)]))
+
+(defmacro cond-let
+  [& clauses]
+  (when-let [[binding then & more] (seq clauses)]
+    (if (= binding :else)
+      then
+      `(if-let ~binding
+         ~then
+         (cond-let ~@more)))))
+
+(defmacro defenum
+  [name & values]
+  `(do
+     (def ~name ~(into #{} (map keyword values)))
+     ~@(for [v values]
+         `(def ~(symbol (str name "-" v)) ~(keyword v)))))
+
+(defenum color red green blue)
+
+(defmacro ->>?
+  "Like ->> but short-circuits on nil."
+  [x & forms]
+  (if (empty? forms)
+    x
+    (let [[form & more] forms
+          sym (gensym "v")]
+      `(let [~sym ~x]
+         (when (some? ~sym)
+           (->>? ~(if (seq? form)
+                    `(~@form ~sym)
+                    `(~form ~sym))
+                 ~@more))))))
+
+(defn pipeline
+  [in out xf]
+  (go
+    (loop []
+      (when-some [v (<! in)]
+        (doseq [x (sequence xf [v])]
+          (>! out x))
+        (recur)))
+    (a/close! out)))
+
+(def chars-of-interest
+  [\a \b \newline \space \tab \é \o101 \\ \( \)])
+
+(def numbers
+  [0 -1 +2 3.5 -4.25e10 0x7f 0X7F 017 2r1010 36rZZ 1/2 -3/4 100N 1.5M ##Inf ##-Inf ##NaN])
+
+(def tagged
+  [#inst "2024-05-01T12:00:00.000-00:00"
+   #uuid "00000000-0000-0000-0000-000000000000"
+   #:person{:name "Ada" :born 1815}
+   #::{:local true}])
+
+(def ^{:doc "Anonymous functions galore"} fns
+  [#(+ % 1) #(apply str %&) #(vector %1 %2) (fn* [x] x)])
+
+(defn deref-all [refs]
+  (mapv #(if (instance? clojure.lang.IDeref %) @% %) refs))
+
+(def regexes
+  {:email #"[^@\s]+@[^@\s]+\.[a-z]{2,}"
+   :path  #"^/(?:[\w-]+/)*[\w-]+$"
+   :quote #"\"[^\"]*\""})
+
+(comment
+  (with-timing (reduce + (range 1e6)))
+  (cond-let [x (first [])] :empty
+            [y (second [1 2])] y
+            :else :none)
+  (->>? {:a {:b 1}} :a :b inc))
@@ -2,6 +2,8 @@ package token
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -10,6 +12,16 @@ type Token struct {
 	kind    Kind
 	len     uint32
 	literal Literal
+	ident   ident
+	comment commentFlags
+}
+
+func NewIntegerToken(n int, base Base) Token {
+	return Token{kind: KindLiteral, len: uint32(n), literal: Literal{kind: LiteralKindInteger, base: base}}
+}
+
+func NewStringToken(n int) Token {
+	return Token{kind: KindLiteral, len: uint32(n), literal: Literal{kind: LiteralKindString}}
 }
 
 func (t Token) Kind() Kind {
@@ -27,6 +39,34 @@ func (t Token) Literal() Literal {
 	return t.literal
 }
 
+func (t Token) Symbol() Symbol {
+	if k1, k2 := KindSymbol, t.kind; k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Symbol{t.ident}
+}
+
+func (t Token) Keyword() Keyword {
+	if k1, k2 := KindKeyword, t.kind; k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Keyword{t.ident}
+}
+
+func (t Token) Tag() Tag {
+	if k1, k2 := KindTag, t.kind; k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Tag{t.ident}
+}
+
+func (t Token) BlockComment() BlockComment {
+	if k1, k2 := KindBlockComment, t.kind; k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return BlockComment{t.comment}
+}
+
 func (t Token) Format(f fmt.State, c rune) {
 	switch c {
 	case 'v':
@@ -74,6 +114,18 @@ const (
 	KindSymbol
 	KindWhitespace
 	KindLineComment
+	KindOpenSet
+	KindOpenFn
+	KindVarQuote
+	KindDiscard
+	KindReaderConditional
+	KindReaderConditionalSplicing
+	KindSymbolicValue
+	KindNamespacedMap
+	KindTag
+	KindKeyword
+	KindBlockComment
+	KindComma
 )
 
 func (k Kind) string() string {
@@ -112,15 +164,189 @@ func (k Kind) string() string {
 		return "whitespace"
 	case KindLineComment:
 		return "line-comment"
+	case KindOpenSet:
+		return "open-set"
+	case KindOpenFn:
+		return "open-fn"
+	case KindVarQuote:
+		return "var-quote"
+	case KindDiscard:
+		return "discard"
+	case KindReaderConditional:
+		return "reader-conditional"
+	case KindReaderConditionalSplicing:
+		return "reader-conditional-splicing"
+	case KindSymbolicValue:
+		return "symbolic-value"
+	case KindNamespacedMap:
+		return "namespaced-map"
+	case KindTag:
+		return "tag"
+	case KindKeyword:
+		return "keyword"
+	case KindBlockComment:
+		return "block-comment"
+	case KindComma:
+		return "comma"
 	default:
 		panic(fmt.Errorf("unknown kind: %v", k))
 	}
 }
 
+type Symbol struct {
+	i ident
+}
+
+func (s Symbol) HasNamespace() bool {
+	return s.i.hasNamespace()
+}
+
+func (s Symbol) NamespaceOffset() int {
+	return 0
+}
+
+func (s Symbol) NamespaceLen() int {
+	return int(s.i.nsLen)
+}
+
+func (s Symbol) NameOffset() int {
+	return s.i.nameOffset(0)
+}
+
+func (s Symbol) Namespace(src string) string {
+	return src[:s.NamespaceLen()]
+}
+
+func (s Symbol) Name(src string) string {
+	return src[s.NameOffset():]
+}
+
+func (s Symbol) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{HasNamespace:%t NamespaceLen:%d}", s, s.HasNamespace(), s.NamespaceLen())
+	} else {
+		fmt.Fprintf(f, "{%t %d}", s.HasNamespace(), s.NamespaceLen())
+	}
+}
+
+type Keyword struct {
+	i ident
+}
+
+func (k Keyword) AutoResolved() bool {
+	return k.i.flags&identFlagAutoResolved != 0
+}
+
+func (k Keyword) HasNamespace() bool {
+	return k.i.hasNamespace()
+}
+
+func (k Keyword) NamespaceOffset() int {
+	if k.AutoResolved() {
+		return 2
+	}
+	return 1
+}
+
+func (k Keyword) NamespaceLen() int {
+	return int(k.i.nsLen)
+}
+
+func (k Keyword) NameOffset() int {
+	return k.i.nameOffset(k.NamespaceOffset())
+}
+
+func (k Keyword) Namespace(src string) string {
+	offset := k.NamespaceOffset()
+	return src[offset : offset+k.NamespaceLen()]
+}
+
+func (k Keyword) Name(src string) string {
+	return src[k.NameOffset():]
+}
+
+func (k Keyword) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{AutoResolved:%t HasNamespace:%t NamespaceLen:%d}", k, k.AutoResolved(), k.HasNamespace(), k.NamespaceLen())
+	} else {
+		fmt.Fprintf(f, "{%t %t %d}", k.AutoResolved(), k.HasNamespace(), k.NamespaceLen())
+	}
+}
+
+type Tag struct {
+	i ident
+}
+
+func (t Tag) HasNamespace() bool {
+	return t.i.hasNamespace()
+}
+
+func (t Tag) NamespaceOffset() int {
+	return 1
+}
+
+func (t Tag) NamespaceLen() int {
+	return int(t.i.nsLen)
+}
+
+func (t Tag) NameOffset() int {
+	return t.i.nameOffset(t.NamespaceOffset())
+}
+
+func (t Tag) Namespace(src string) string {
+	offset := t.NamespaceOffset()
+	return src[offset : offset+t.NamespaceLen()]
+}
+
+func (t Tag) Name(src string) string {
+	return src[t.NameOffset():]
+}
+
+func (t Tag) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{HasNamespace:%t NamespaceLen:%d}", t, t.HasNamespace(), t.NamespaceLen())
+	} else {
+		fmt.Fprintf(f, "{%t %d}", t.HasNamespace(), t.NamespaceLen())
+	}
+}
+
+type BlockComment struct {
+	flags commentFlags
+}
+
+func (c BlockComment) Unterminated() bool {
+	return c.flags&commentFlagUnterminated != 0
+}
+
+func (c BlockComment) Format(f fmt.State, r rune) {
+	if r == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{Unterminated:%t}", c, c.Unterminated())
+	} else {
+		fmt.Fprintf(f, "{%t}", c.Unterminated())
+	}
+}
+
+type ident struct {
+	nsLen uint32
+	flags identFlags
+}
+
+func (i ident) hasNamespace() bool {
+	return i.flags&identFlagNamespace != 0
+}
+
+func (i ident) nameOffset(prefix int) int {
+	if i.hasNamespace() {
+		return prefix + int(i.nsLen) + 1
+	}
+	return prefix
+}
+
 type Literal struct {
-	kind  LiteralKind
-	base  Base
-	flags literalFlags
+	kind   LiteralKind
+	base   Base
+	flags  literalFlags
+	offset uint32
 }
 
 func (l Literal) Kind() LiteralKind {
@@ -141,6 +367,13 @@ func (l Literal) Float() Float {
 	return Float{l}
 }
 
+func (l Literal) Ratio() Ratio {
+	if k1, k2 := LiteralKindRatio, l.Kind(); k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Ratio{l}
+}
+
 func (l Literal) String() String {
 	if k1, k2 := LiteralKindString, l.Kind(); k1 != k2 {
 		panic(fmt.Errorf("expected %v, got %v", k1, k2))
@@ -155,6 +388,20 @@ func (l Literal) Character() Character {
 	return Character{l}
 }
 
+func (l Literal) Regex() Regex {
+	if k1, k2 := LiteralKindRegex, l.Kind(); k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Regex{l}
+}
+
+func (l Literal) Boolean() Boolean {
+	if k1, k2 := LiteralKindBoolean, l.Kind(); k1 != k2 {
+		panic(fmt.Errorf("expected %v, got %v", k1, k2))
+	}
+	return Boolean{l}
+}
+
 func (l Literal) Format(f fmt.State, c rune) {
 	switch c {
 	case 'v':
@@ -168,6 +415,12 @@ func (l Literal) Format(f fmt.State, c rune) {
 				fmt.Fprintf(f, "%T{%+v}", l, l.String())
 			case LiteralKindCharacter:
 				fmt.Fprintf(f, "%T{%+v}", l, l.Character())
+			case LiteralKindRegex:
+				fmt.Fprintf(f, "%T{%+v}", l, l.Regex())
+			case LiteralKindRatio:
+				fmt.Fprintf(f, "%T{%+v}", l, l.Ratio())
+			case LiteralKindBoolean:
+				fmt.Fprintf(f, "%T{%+v}", l, l.Boolean())
 			}
 		} else {
 			switch l.kind {
@@ -179,6 +432,12 @@ func (l Literal) Format(f fmt.State, c rune) {
 				fmt.Fprintf(f, "{%T%v}", l.String(), l.String())
 			case LiteralKindCharacter:
 				fmt.Fprintf(f, "{%T%v}", l.Character(), l.Character())
+			case LiteralKindRegex:
+				fmt.Fprintf(f, "{%T%v}", l.Regex(), l.Regex())
+			case LiteralKindRatio:
+				fmt.Fprintf(f, "{%T%v}", l.Ratio(), l.Ratio())
+			case LiteralKindBoolean:
+				fmt.Fprintf(f, "{%T%v}", l.Boolean(), l.Boolean())
 			}
 		}
 	default:
@@ -191,6 +450,12 @@ func (l Literal) Format(f fmt.State, c rune) {
 			fmt.Fprintf(f, "{%T}", l.String())
 		case LiteralKindCharacter:
 			fmt.Fprintf(f, "{%T}", l.Character())
+		case LiteralKindRegex:
+			fmt.Fprintf(f, "{%T}", l.Regex())
+		case LiteralKindRatio:
+			fmt.Fprintf(f, "{%T}", l.Ratio())
+		case LiteralKindBoolean:
+			fmt.Fprintf(f, "{%T}", l.Boolean())
 		}
 	}
 }
@@ -207,11 +472,19 @@ func (l Integer) EmptyInt() bool {
 	return l.l.flags&literalFlagEmptyInt != 0
 }
 
+func (l Integer) BigInt() bool {
+	return l.l.flags&literalFlagBigInt != 0
+}
+
+func (l Integer) InvalidRadix() bool {
+	return l.l.flags&literalFlagInvalidRadix != 0
+}
+
 func (l Integer) Format(f fmt.State, c rune) {
 	if c == 'v' && f.Flag('+') {
-		fmt.Fprintf(f, "%T{Base:%d EmptyInt:%t}", l, l.Base(), l.EmptyInt())
+		fmt.Fprintf(f, "%T{Base:%d EmptyInt:%t BigInt:%t InvalidRadix:%t}", l, l.Base(), l.EmptyInt(), l.BigInt(), l.InvalidRadix())
 	} else {
-		fmt.Fprintf(f, "{%d %t}", l.Base(), l.EmptyInt())
+		fmt.Fprintf(f, "{%d %t %t %t}", l.Base(), l.EmptyInt(), l.BigInt(), l.InvalidRadix())
 	}
 }
 
@@ -223,11 +496,27 @@ func (l Float) EmptyExponent() bool {
 	return l.l.flags&literalFlagEmptyExponent != 0
 }
 
+func (l Float) BigDecimal() bool {
+	return l.l.flags&literalFlagBigDecimal != 0
+}
+
 func (l Float) Format(f fmt.State, c rune) {
 	if c == 'v' && f.Flag('+') {
-		fmt.Fprintf(f, "%T{EmptyExponent:%t}", l, l.EmptyExponent())
+		fmt.Fprintf(f, "%T{EmptyExponent:%t BigDecimal:%t}", l, l.EmptyExponent(), l.BigDecimal())
 	} else {
-		fmt.Fprintf(f, "{%t}", l.EmptyExponent())
+		fmt.Fprintf(f, "{%t %t}", l.EmptyExponent(), l.BigDecimal())
+	}
+}
+
+type Ratio struct {
+	l Literal
+}
+
+func (l Ratio) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{}", l)
+	} else {
+		fmt.Fprintf(f, "{}")
 	}
 }
 
@@ -239,11 +528,23 @@ func (l String) Unterminated() bool {
 	return l.l.flags&literalFlagUnterminated != 0
 }
 
+func (l String) InvalidEscape() bool {
+	return l.l.flags&literalFlagInvalidEscape != 0
+}
+
+func (l String) InvalidEscapeOffset() int {
+	return int(l.l.offset)
+}
+
 func (l String) Format(f fmt.State, c rune) {
 	if c == 'v' && f.Flag('+') {
-		fmt.Fprintf(f, "%T{Unterminated:%t}", l, l.Unterminated())
+		if l.InvalidEscape() {
+			fmt.Fprintf(f, "%T{Unterminated:%t InvalidEscape:%t InvalidEscapeOffset:%d}", l, l.Unterminated(), l.InvalidEscape(), l.InvalidEscapeOffset())
+		} else {
+			fmt.Fprintf(f, "%T{Unterminated:%t InvalidEscape:%t}", l, l.Unterminated(), l.InvalidEscape())
+		}
 	} else {
-		fmt.Fprintf(f, "{%t}", l.Unterminated())
+		fmt.Fprintf(f, "{%t %t}", l.Unterminated(), l.InvalidEscape())
 	}
 }
 
@@ -263,6 +564,34 @@ func (l Character) Format(f fmt.State, c rune) {
 	}
 }
 
+type Regex struct {
+	l Literal
+}
+
+func (l Regex) Unterminated() bool {
+	return l.l.flags&literalFlagUnterminated != 0
+}
+
+func (l Regex) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{Unterminated:%t}", l, l.Unterminated())
+	} else {
+		fmt.Fprintf(f, "{%t}", l.Unterminated())
+	}
+}
+
+type Boolean struct {
+	l Literal
+}
+
+func (l Boolean) Format(f fmt.State, c rune) {
+	if c == 'v' && f.Flag('+') {
+		fmt.Fprintf(f, "%T{}", l)
+	} else {
+		fmt.Fprintf(f, "{}")
+	}
+}
+
 type LiteralKind uint8
 
 const (
@@ -270,6 +599,9 @@ const (
 	LiteralKindFloat
 	LiteralKindString
 	LiteralKindCharacter
+	LiteralKindRegex
+	LiteralKindRatio
+	LiteralKindBoolean
 )
 
 type Base uint8
@@ -281,29 +613,83 @@ const (
 	BaseHexadecimal Base = 16
 )
 
+const (
+	minBase = 2
+	maxBase = 36
+)
+
 type TokenConsumer interface {
 	ConsumeToken(Token)
 }
 
-func Tokenize(consumer TokenConsumer, s string) error {
-	t := &tokenizer{consumer: consumer, s: s}
+func Tokenize(consumer TokenConsumer, s string, opts ...Option) error {
+	sc := newScanner(s, opts)
 	for {
-		token := t.Advance()
-		if token.Kind() == kindNone {
-			break
+		token, ok := sc.next()
+		if !ok {
+			return sc.err()
 		}
 		consumer.ConsumeToken(token)
 	}
-	return nil
+}
+
+type scanner struct {
+	t       tokenizer
+	src     string
+	opts    options
+	offset  int
+	located int
+	cursor  Cursor
+	diags   Diagnostics
+	stopped bool
+}
+
+func newScanner(s string, opts []Option) *scanner {
+	sc := &scanner{src: s, opts: newOptions(opts), cursor: *NewCursor()}
+	sc.t = tokenizer{s: s, eof: true, dialect: &sc.opts.dialect, ascii: &sc.opts.ascii}
+	return sc
+}
+
+func (sc *scanner) next() (Token, bool) {
+	if sc.stopped {
+		return Token{}, false
+	}
+	token := sc.t.Advance()
+	if token.Kind() == kindNone {
+		return Token{}, false
+	}
+
+	text := sc.src[sc.offset : sc.offset+int(token.len)]
+	if found := sc.opts.diagnose(token, text); len(found) > 0 {
+		sc.cursor.Advance(sc.src[sc.located:sc.offset])
+		sc.located = sc.offset
+		found.locate(sc.cursor, text)
+		sc.diags = append(sc.diags, found...)
+		if sc.opts.failFast && found.Err() != nil {
+			sc.stopped = true
+			return Token{}, false
+		}
+	}
+	sc.offset += int(token.len)
+	return token, true
+}
+
+func (sc *scanner) err() error {
+	return sc.diags.Err()
 }
 
 type tokenizer struct {
-	consumer     TokenConsumer
 	s            string
 	posWithinTok uint32
+	tokStart     string
+	eof          bool
+	short        bool
+	dialect      *Dialect
+	ascii        *asciiTable
 }
 
 func (t *tokenizer) Advance() Token {
+	t.tokStart = t.s
 	token := t.nextToken()
 	token.len = t.posWithinToken()
 	t.resetPosWithinToken()
@@ -320,6 +706,14 @@ func (t *tokenizer) nextToken() Token {
 		return t.whitespace()
 	}
 
+	if firstChar == ':' {
+		return t.keyword()
+	}
+
+	if (firstChar == '+' || firstChar == '-') && t.isDecimal(t.first()) {
+		return t.numeric(t.bump())
+	}
+
 	if t.isSymbolStart(firstChar) {
 		return t.symbol()
 	}
@@ -328,6 +722,10 @@ func (t *tokenizer) nextToken() Token {
 		return t.numeric(firstChar)
 	}
 
+	if t.dialect.DataOnly && strings.ContainsRune("'`~@^", firstChar) {
+		return Token{kind: KindInvalid}
+	}
+
 	switch firstChar {
 	case '\\':
 		return t.character()
@@ -344,8 +742,14 @@ func (t *tokenizer) nextToken() Token {
 	case '}':
 		return Token{kind: KindCloseBrace}
 	case '[':
+		if t.dialect.BracketsAsParens {
+			return Token{kind: KindOpenParen}
+		}
 		return Token{kind: KindOpenBracket}
 	case ']':
+		if t.dialect.BracketsAsParens {
+			return Token{kind: KindCloseParen}
+		}
 		return Token{kind: KindCloseBracket}
 	case '\'':
 		return Token{kind: KindQuote}
@@ -356,19 +760,31 @@ func (t *tokenizer) nextToken() Token {
 	case '^':
 		return Token{kind: KindMetadata}
 	case '#':
-		return Token{kind: KindDispatch}
+		return t.dispatch()
 	case '~':
 		return t.unquote()
+	case ',':
+		switch t.dialect.Comma {
+		case CommaUnquote:
+			return t.unquote()
+		case CommaToken:
+			return Token{kind: KindComma}
+		default:
+			return Token{kind: KindInvalid}
+		}
 	default:
 		return Token{kind: KindInvalid}
 	}
 }
 
 func (t *tokenizer) whitespace() Token {
-	for t.isWhitespace(t.first()) {
+	for {
+		t.skipASCII(classWhitespace)
+		if !t.isWhitespace(t.first()) {
+			return Token{kind: KindWhitespace}
+		}
 		t.bump()
 	}
-	return Token{kind: KindWhitespace}
 }
 
 func (t *tokenizer) numeric(firstDigit rune) Token {
@@ -380,15 +796,15 @@ func (t *tokenizer) numeric(firstDigit rune) Token {
 		case 'b':
 			t.bump()
 			flags.setIf(!t.eatBinaryDigits(), literalFlagEmptyInt)
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: BaseBinary, flags: flags}}
+			return t.integer(BaseBinary, flags)
 		case 'o':
 			t.bump()
 			flags.setIf(!t.eatOctalDigits(), literalFlagEmptyInt)
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: BaseOctal, flags: flags}}
+			return t.integer(BaseOctal, flags)
 		case 'x':
 			t.bump()
 			flags.setIf(!t.eatHexDigits(), literalFlagEmptyInt)
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: BaseHexadecimal, flags: flags}}
+			return t.integer(BaseHexadecimal, flags)
 		}
 	}
 
@@ -397,7 +813,7 @@ func (t *tokenizer) numeric(firstDigit rune) Token {
 	switch t.first() {
 	case '.':
 		if !t.isDecimalContinue(t.second()) {
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: BaseDecimal, flags: flags}}
+			return t.integer(BaseDecimal, flags)
 		}
 
 		t.bump()
@@ -407,14 +823,57 @@ func (t *tokenizer) numeric(firstDigit rune) Token {
 			t.bump()
 			flags.setIf(!t.eatFloatExponent(), literalFlagEmptyExponent)
 		}
-		return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindFloat, flags: flags}}
+		return t.float(flags)
 	case 'e', 'E':
 		t.bump()
 		flags.setIf(!t.eatFloatExponent(), literalFlagEmptyExponent)
-		return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindFloat, flags: flags}}
+		return t.float(flags)
+	case 'M':
+		return t.float(flags)
+	case 'r', 'R':
+		return t.radix()
+	case '/':
+		if !t.isDecimal(t.second()) {
+			return t.integer(BaseDecimal, flags)
+		}
+		t.bump()
+		t.eatDecimalDigits()
+		return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindRatio, base: BaseDecimal, flags: flags}}
 	default:
-		return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: BaseDecimal, flags: flags}}
+		return t.integer(BaseDecimal, flags)
+	}
+}
+
+func (t *tokenizer) integer(base Base, flags literalFlags) Token {
+	if t.first() == 'N' {
+		t.bump()
+		flags.setIf(true, literalFlagBigInt)
 	}
+	return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: base, flags: flags}}
+}
+
+func (t *tokenizer) float(flags literalFlags) Token {
+	if t.first() == 'M' {
+		t.bump()
+		flags.setIf(true, literalFlagBigDecimal)
+	}
+	return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindFloat, flags: flags}}
+}
+
+func (t *tokenizer) radix() Token {
+	var flags literalFlags
+
+	digits := strings.TrimLeft(t.tokStart[:t.posWithinToken()], "+-")
+	base, err := strconv.Atoi(digits)
+	valid := err == nil && base >= minBase && base <= maxBase
+	if !valid {
+		base = 0
+	}
+	flags.setIf(!valid, literalFlagInvalidRadix)
+
+	t.bump()
+	flags.setIf(!t.eatRadixDigits(Base(base)), literalFlagEmptyInt)
+	return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindInteger, base: Base(base), flags: flags}}
 }
 
 func (t *tokenizer) character() Token {
@@ -432,31 +891,162 @@ func (t *tokenizer) character() Token {
 
 func (t *tokenizer) string() Token {
 	var flags literalFlags
+	var offset uint32
 
 	for {
+		t.skipUntil('"', '\\')
 		c := t.bump()
 		switch c {
 		case charEOF:
 			flags.setIf(true, literalFlagUnterminated)
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindString, flags: flags}}
+			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindString, flags: flags, offset: offset}}
 		case '"':
-			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindString, flags: flags}}
+			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindString, flags: flags, offset: offset}}
 		case '\\':
-			if t.first() == '"' || t.first() == '\\' {
-				t.bump()
+			if !t.escape() && flags&literalFlagInvalidEscape == 0 {
+				flags.setIf(true, literalFlagInvalidEscape)
+				offset = t.posWithinToken() - 1
 			}
 		}
 	}
 }
 
-func (t *tokenizer) lineComment() Token {
-	for {
-		c := t.first()
-		if c == charEOF || c == '\n' {
-			return Token{kind: KindLineComment}
+func (t *tokenizer) escape() bool {
+	if !t.eof && len(t.s) < maxEscapeLen {
+		t.short = true
+	}
+	if len(t.s) == 0 {
+		return true
+	}
+
+	_, size, ok := scanEscape(t.s)
+	if !ok {
+		return false
+	}
+	t.s = t.s[size:]
+	t.posWithinTok += uint32(size)
+	return true
+}
+
+func (t *tokenizer) dispatch() Token {
+	c := t.first()
+	if t.dialect.DataOnly && strings.ContainsRune("(\"'?", c) {
+		return Token{kind: KindDispatch}
+	}
+
+	switch c {
+	case '{':
+		t.bump()
+		return Token{kind: KindOpenSet}
+	case '(':
+		t.bump()
+		return Token{kind: KindOpenFn}
+	case '\'':
+		t.bump()
+		return Token{kind: KindVarQuote}
+	case '_':
+		t.bump()
+		return Token{kind: KindDiscard}
+	case '"':
+		t.bump()
+		return t.regex()
+	case '?':
+		t.bump()
+		if t.first() == '@' {
+			t.bump()
+			return Token{kind: KindReaderConditionalSplicing}
+		}
+		return Token{kind: KindReaderConditional}
+	case '#':
+		t.bump()
+		if !t.isSymbolStart(t.first()) {
+			return Token{kind: KindInvalid}
 		}
+		t.eatSymbol()
+		return Token{kind: KindSymbolicValue}
+	case ':':
 		t.bump()
+		t.eatSymbol()
+		return Token{kind: KindNamespacedMap}
+	case '|':
+		if t.dialect.BlockComments {
+			t.bump()
+			return t.blockComment()
+		}
+	case '\\':
+		if t.dialect.HashCharacters {
+			t.bump()
+			return t.character()
+		}
+	case ';':
+		if t.dialect.DatumComments {
+			t.bump()
+			return Token{kind: KindDiscard}
+		}
 	}
+
+	if t.isSymbolStart(c) {
+		t.eatSymbol()
+		if t.dialect.Booleans {
+			switch t.tokStart[:t.posWithinToken()] {
+			case "#t", "#f", "#true", "#false":
+				return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindBoolean}}
+			}
+		}
+		return Token{kind: KindTag, ident: t.ident(1, 0)}
+	}
+	return Token{kind: KindDispatch}
+}
+
+func (t *tokenizer) regex() Token {
+	var flags literalFlags
+
+	for {
+		c := t.bump()
+		switch c {
+		case charEOF:
+			flags.setIf(true, literalFlagUnterminated)
+			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindRegex, flags: flags}}
+		case '"':
+			return Token{kind: KindLiteral, literal: Literal{kind: LiteralKindRegex, flags: flags}}
+		case '\\':
+			t.bump()
+		}
+	}
+}
+
+func (t *tokenizer) lineComment() Token {
+	i := strings.IndexByte(t.s, '\n')
+	if i == -1 {
+		i = len(t.s)
+	}
+	t.skip(i)
+	t.first()
+	return Token{kind: KindLineComment}
+}
+
+func (t *tokenizer) blockComment() Token {
+	var flags commentFlags
+	depth := 1
+	for depth > 0 {
+		t.skipUntil('|', '#')
+		switch t.bump() {
+		case charEOF:
+			flags.setIf(true, commentFlagUnterminated)
+			return Token{kind: KindBlockComment, comment: flags}
+		case '|':
+			if t.first() == '#' {
+				t.bump()
+				depth--
+			}
+		case '#':
+			if t.first() == '|' {
+				t.bump()
+				depth++
+			}
+		}
+	}
+	return Token{kind: KindBlockComment}
 }
 
 func (t *tokenizer) unquote() Token {
@@ -468,19 +1058,42 @@ func (t *tokenizer) unquote() Token {
 }
 
 func (t *tokenizer) isWhitespace(c rune) bool {
+	if uint32(c) < utf8.RuneSelf {
+		return t.ascii[c]&classWhitespace != 0
+	}
+	return t.isWhitespaceRune(c)
+}
+
+func (t *tokenizer) isWhitespaceRune(c rune) bool {
 	switch c {
 	case '\u0009', // \t
 		'\u000A', // \n
 		'\u000B', // vertical tab
 		'\u000C', // form feed
 		'\u000D', // \r
+		'\u001C', // FILE SEPARATOR
+		'\u001D', // GROUP SEPARATOR
+		'\u001E', // RECORD SEPARATOR
+		'\u001F', // UNIT SEPARATOR
 		'\u0020', // space
-		'\u0085', // NEXT LINE from latin1
-		'\u200E', // LEFT-TO-RIGHT MARK
-		'\u200F', // RIGHT-TO-LEFT MARK
+		'\u1680', // OGHAM SPACE MARK
+		'\u2000', // EN QUAD
+		'\u2001', // EM QUAD
+		'\u2002', // EN SPACE
+		'\u2003', // EM SPACE
+		'\u2004', // THREE-PER-EM SPACE
+		'\u2005', // FOUR-PER-EM SPACE
+		'\u2006', // SIX-PER-EM SPACE
+		'\u2008', // PUNCTUATION SPACE
+		'\u2009', // THIN SPACE
+		'\u200A', // HAIR SPACE
 		'\u2028', // LINE SEPARATOR
-		'\u2029': // PARAGRAPH SEPARATOR
+		'\u2029', // PARAGRAPH SEPARATOR
+		'\u205F', // MEDIUM MATHEMATICAL SPACE
+		'\u3000': // IDEOGRAPHIC SPACE
 		return true
+	case ',':
+		return t.dialect.Comma == CommaWhitespace
 	default:
 		return false
 	}
@@ -488,16 +1101,52 @@ func (t *tokenizer) isWhitespace(c rune) bool {
 
 func (t *tokenizer) symbol() Token {
 	t.eatSymbol()
-	return Token{kind: KindSymbol}
+	return Token{kind: KindSymbol, ident: t.ident(0, 0)}
+}
+
+func (t *tokenizer) keyword() Token {
+	var flags identFlags
+	prefix := 1
+	if t.first() == ':' {
+		t.bump()
+		flags.setIf(true, identFlagAutoResolved)
+		prefix++
+	}
+	t.eatSymbol()
+	return Token{kind: KindKeyword, ident: t.ident(prefix, flags)}
+}
+
+func (t *tokenizer) ident(prefix int, flags identFlags) ident {
+	name := t.tokStart[prefix:t.posWithinToken()]
+	nsLen := strings.LastIndexByte(name, '/')
+	if len(name) > 2 && strings.HasSuffix(name, "//") {
+		nsLen = len(name) - 2
+	}
+	if nsLen <= 0 || nsLen == len(name)-1 {
+		return ident{flags: flags}
+	}
+	flags.setIf(true, identFlagNamespace)
+	return ident{nsLen: uint32(nsLen), flags: flags}
 }
 
 func (t *tokenizer) eatSymbol() {
-	for t.isSymbolContinue(t.first()) {
+	for {
+		t.skipASCII(classSymbolContinue)
+		if !t.isSymbolContinue(t.first()) {
+			return
+		}
 		t.bump()
 	}
 }
 
 func (t *tokenizer) isSymbolStart(c rune) bool {
+	if uint32(c) < utf8.RuneSelf {
+		return t.ascii[c]&classSymbolStart != 0
+	}
+	return t.isSymbolStartRune(c)
+}
+
+func (t *tokenizer) isSymbolStartRune(c rune) bool {
 	if unicode.IsLetter(c) {
 		return true
 	}
@@ -514,15 +1163,24 @@ func (t *tokenizer) isSymbolStart(c rune) bool {
 		'>',
 		'=',
 		'.',
+		'%',
+		'&',
 		':':
 		return true
 	default:
-		return false
+		return t.dialect.SymbolChars != "" && strings.ContainsRune(t.dialect.SymbolChars, c)
 	}
 }
 
 func (t *tokenizer) isSymbolContinue(c rune) bool {
-	if t.isSymbolStart(c) {
+	if uint32(c) < utf8.RuneSelf {
+		return t.ascii[c]&classSymbolContinue != 0
+	}
+	return t.isSymbolContinueRune(c)
+}
+
+func (t *tokenizer) isSymbolContinueRune(c rune) bool {
+	if t.isSymbolStartRune(c) {
 		return true
 	}
 
@@ -531,7 +1189,7 @@ func (t *tokenizer) isSymbolContinue(c rune) bool {
 	}
 
 	switch c {
-	case '\'':
+	case '\'', '#':
 		return true
 	default:
 		return false
@@ -582,6 +1240,31 @@ func (t *tokenizer) isHexContinue(c rune) bool {
 	return t.isDecimalContinue(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
 }
 
+func (t *tokenizer) eatRadixDigits(base Base) (found bool) {
+	for t.isRadixContinue(t.first(), base) {
+		found = true
+		t.bump()
+	}
+	return found
+}
+
+func (t *tokenizer) isRadixContinue(c rune, base Base) bool {
+	var v rune
+	switch {
+	case c == '_':
+		return true
+	case c >= '0' && c <= '9':
+		v = c - '0'
+	case c >= 'a' && c <= 'z':
+		v = c - 'a' + 10
+	case c >= 'A' && c <= 'Z':
+		v = c - 'A' + 10
+	default:
+		return false
+	}
+	return base == 0 || v < rune(base)
+}
+
 func (t *tokenizer) eatDecimalDigits() (found bool) {
 	for t.isDecimalContinue(t.first()) {
 		found = true
@@ -599,32 +1282,44 @@ func (t *tokenizer) isDecimalContinue(c rune) bool {
 }
 
 func (t *tokenizer) bump() rune {
-	if len(t.s) == 0 {
+	r, size := t.decode(t.s)
+	if size == 0 {
 		return charEOF
 	}
-	r, size := utf8.DecodeRuneInString(t.s)
 	t.s = t.s[size:]
 	t.posWithinTok += uint32(size)
 	return r
 }
 
 func (t *tokenizer) first() rune {
-	if len(t.s) == 0 {
-		return charEOF
-	}
-	r, _ := utf8.DecodeRuneInString(t.s)
+	r, _ := t.decode(t.s)
 	return r
 }
 
 func (t *tokenizer) second() rune {
-	if len(t.s) <= 1 {
+	_, size := t.decode(t.s)
+	if size == 0 {
 		return charEOF
 	}
-	_, s := utf8.DecodeRuneInString(t.s)
-	r, _ := utf8.DecodeRuneInString(t.s[s:])
+	r, _ := t.decode(t.s[size:])
 	return r
 }
 
+func (t *tokenizer) decode(s string) (rune, int) {
+	if len(s) > 0 && s[0] < utf8.RuneSelf {
+		return rune(s[0]), 1
+	}
+	if len(s) == 0 {
+		t.short = t.short || !t.eof
+		return charEOF, 0
+	}
+	if !t.eof && !utf8.FullRuneInString(s) {
+		t.short = true
+		return charEOF, 0
+	}
+	return utf8.DecodeRuneInString(s)
+}
+
 func (t *tokenizer) posWithinToken() uint32 {
 	return t.posWithinTok
 }
@@ -639,8 +1334,12 @@ type literalFlags uint8
 
 const (
 	literalFlagEmptyInt      literalFlags = 1 << 0
+	literalFlagBigInt        literalFlags = 1 << 1
+	literalFlagInvalidRadix  literalFlags = 1 << 2
 	literalFlagEmptyExponent literalFlags = 1 << 0
+	literalFlagBigDecimal    literalFlags = 1 << 1
 	literalFlagUnterminated  literalFlags = 1 << 0
+	literalFlagInvalidEscape literalFlags = 1 << 1
 	literalFlagMissingChar   literalFlags = 1 << 0
 )
 
@@ -651,3 +1350,32 @@ func (f *literalFlags) setIf(cond bool, flag literalFlags) {
 		*f &= ^flag
 	}
 }
+
+type commentFlags uint8
+
+const (
+	commentFlagUnterminated commentFlags = 1 << 0
+)
+
+func (f *commentFlags) setIf(cond bool, flag commentFlags) {
+	if cond {
+		*f |= flag
+	} else {
+		*f &= ^flag
+	}
+}
+
+type identFlags uint8
+
+const (
+	identFlagNamespace    identFlags = 1 << 0
+	identFlagAutoResolved identFlags = 1 << 1
+)
+
+func (f *identFlags) setIf(cond bool, flag identFlags) {
+	if cond {
+		*f |= flag
+	} else {
+		*f &= ^flag
+	}
+}
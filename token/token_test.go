@@ -1,6 +1,7 @@
 package token
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -246,6 +247,98 @@ func TestTokenize(t *testing.T) {
 				newToken(KindSymbol, 1),
 			},
 		},
+		{
+			name: "signed numbers",
+			source: `
+-5 +3.0 -0x1F +1e5 -1/2 - -> +a -.5
+			`,
+			expected: []Token{
+				newInteger(2, BaseDecimal),
+				newToken(KindWhitespace, 1),
+				newFloat(4),
+				newToken(KindWhitespace, 1),
+				newInteger(5, BaseHexadecimal),
+				newToken(KindWhitespace, 1),
+				newFloat(4),
+				newToken(KindWhitespace, 1),
+				newRatio(4),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 3),
+			},
+		},
+		{
+			name: "number suffixes",
+			source: `
+42N 0xFFN 1.5M 1M 1e10M 2.5N
+			`,
+			expected: []Token{
+				newInteger(3, BaseDecimal, literalFlagBigInt),
+				newToken(KindWhitespace, 1),
+				newInteger(5, BaseHexadecimal, literalFlagBigInt),
+				newToken(KindWhitespace, 1),
+				newFloat(4, literalFlagBigDecimal),
+				newToken(KindWhitespace, 1),
+				newFloat(2, literalFlagBigDecimal),
+				newToken(KindWhitespace, 1),
+				newFloat(5, literalFlagBigDecimal),
+				newToken(KindWhitespace, 1),
+				newFloat(3),
+				newToken(KindSymbol, 1),
+			},
+		},
+		{
+			name: "radix integers",
+			source: `
+2r1010 36rZZ 16R_ff_ -8r17 3r12 3r34 1r0 37r1 0r 10r
+			`,
+			expected: []Token{
+				newInteger(6, BaseBinary),
+				newToken(KindWhitespace, 1),
+				newInteger(5, 36),
+				newToken(KindWhitespace, 1),
+				newInteger(7, BaseHexadecimal),
+				newToken(KindWhitespace, 1),
+				newInteger(5, BaseOctal),
+				newToken(KindWhitespace, 1),
+				newInteger(4, 3),
+				newToken(KindWhitespace, 1),
+				newInteger(2, 3, literalFlagEmptyInt),
+				newInteger(2, BaseDecimal),
+				newToken(KindWhitespace, 1),
+				newInteger(3, 0, literalFlagInvalidRadix),
+				newToken(KindWhitespace, 1),
+				newInteger(4, 0, literalFlagInvalidRadix),
+				newToken(KindWhitespace, 1),
+				newInteger(2, 0, literalFlagInvalidRadix, literalFlagEmptyInt),
+				newToken(KindWhitespace, 1),
+				newInteger(3, BaseDecimal, literalFlagEmptyInt),
+			},
+		},
+		{
+			name: "ratios",
+			source: `
+1/3 -22/7 1_000/3 1/ 1/a
+			`,
+			expected: []Token{
+				newRatio(3),
+				newToken(KindWhitespace, 1),
+				newRatio(5),
+				newToken(KindWhitespace, 1),
+				newRatio(7),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindSymbol, 2),
+			},
+		},
 		{
 			name: "multiline string",
 			source: `
@@ -275,6 +368,62 @@ hello
 				newString(1, literalFlagUnterminated),
 			},
 		},
+		{
+			name: "valid string escapes",
+			source: `
+"\t\b\n\f\r\"\\é\0\12\377"
+			`,
+			expected: []Token{
+				newString(27),
+			},
+		},
+		{
+			name: "invalid string escape",
+			source: `
+"ab\qc\xd"
+			`,
+			expected: []Token{
+				newInvalidEscapeString(10, 3),
+			},
+		},
+		{
+			name: "quote escape",
+			source: `
+"\'"
+			`,
+			expected: []Token{
+				newInvalidEscapeString(4, 1),
+			},
+		},
+		{
+			name: "short unicode escape",
+			source: `
+"\u12" "\u"
+			`,
+			expected: []Token{
+				newInvalidEscapeString(6, 1),
+				newToken(KindWhitespace, 1),
+				newInvalidEscapeString(4, 1),
+			},
+		},
+		{
+			name: "octal escape out of range",
+			source: `
+"\400"
+			`,
+			expected: []Token{
+				newInvalidEscapeString(6, 1),
+			},
+		},
+		{
+			name: "invalid escape in unterminated string",
+			source: `
+"\q
+			`,
+			expected: []Token{
+				newInvalidEscapeString(3, 1, literalFlagUnterminated),
+			},
+		},
 		{
 			name: "valid characters",
 			source: `
@@ -330,17 +479,83 @@ hello
 a/b.c
 +-*/!_?<>=:.
 a'
+%1 %& &
 			`,
 			expected: []Token{
-				newToken(KindSymbol, 15),
+				newToken(KindKeyword, 15),
 				newToken(KindWhitespace, 1),
 				newToken(KindSymbol, 5),
 				newToken(KindWhitespace, 1),
-				newToken(KindSymbol, 5),
+				newSymbol(5, 1),
+				newToken(KindWhitespace, 1),
+				newSymbol(12, 3),
 				newToken(KindWhitespace, 1),
-				newToken(KindSymbol, 12),
+				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 2),
 				newToken(KindWhitespace, 1),
 				newToken(KindSymbol, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+			},
+		},
+		{
+			name: "auto-gensym symbols",
+			source: `
+foo# a#b ns/x# :k# foo #bar
+			`,
+			expected: []Token{
+				newToken(KindSymbol, 4),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 3),
+				newToken(KindWhitespace, 1),
+				newSymbol(5, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindKeyword, 3),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 3),
+				newToken(KindWhitespace, 1),
+				newTag(4, 0),
+			},
+		},
+		{
+			name: "namespaced symbols",
+			source: `
+clojure.core/map / clojure.core// a/b/c foo/ ns/:a
+			`,
+			expected: []Token{
+				newSymbol(16, 12),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newSymbol(14, 12),
+				newToken(KindWhitespace, 1),
+				newSymbol(5, 3),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbol, 4),
+				newToken(KindWhitespace, 1),
+				newSymbol(5, 2),
+			},
+		},
+		{
+			name: "keywords",
+			source: `
+:foo :ns/foo ::foo ::alias/foo :1 : :a/
+			`,
+			expected: []Token{
+				newToken(KindKeyword, 4),
+				newToken(KindWhitespace, 1),
+				newKeyword(7, 2, false),
+				newToken(KindWhitespace, 1),
+				newKeyword(5, 0, true),
+				newToken(KindWhitespace, 1),
+				newKeyword(11, 5, true),
+				newToken(KindWhitespace, 1),
+				newToken(KindKeyword, 2),
+				newToken(KindWhitespace, 1),
+				newToken(KindKeyword, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindKeyword, 3),
 			},
 		},
 		{
@@ -444,6 +659,124 @@ a'
 				newToken(KindDispatch, 1),
 			},
 		},
+		{
+			name: "dispatch macros",
+			source: `
+#{} #() #'a #_a #?(:clj 1) #?@(:clj [1])
+			`,
+			expected: []Token{
+				newToken(KindOpenSet, 2),
+				newToken(KindCloseBrace, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindOpenFn, 2),
+				newToken(KindCloseParen, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindVarQuote, 2),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindDiscard, 2),
+				newToken(KindSymbol, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindReaderConditional, 2),
+				newToken(KindOpenParen, 1),
+				newToken(KindKeyword, 4),
+				newToken(KindWhitespace, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseParen, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindReaderConditionalSplicing, 3),
+				newToken(KindOpenParen, 1),
+				newToken(KindKeyword, 4),
+				newToken(KindWhitespace, 1),
+				newToken(KindOpenBracket, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseBracket, 1),
+				newToken(KindCloseParen, 1),
+			},
+		},
+		{
+			name: "symbolic values",
+			source: `
+##Inf ##-Inf ##NaN ##
+			`,
+			expected: []Token{
+				newToken(KindSymbolicValue, 5),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbolicValue, 6),
+				newToken(KindWhitespace, 1),
+				newToken(KindSymbolicValue, 5),
+				newToken(KindWhitespace, 1),
+				newToken(KindInvalid, 2),
+			},
+		},
+		{
+			name: "namespaced maps",
+			source: `
+#:foo.bar{} #::{} #::baz{}
+			`,
+			expected: []Token{
+				newToken(KindNamespacedMap, 9),
+				newToken(KindOpenBrace, 1),
+				newToken(KindCloseBrace, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindNamespacedMap, 3),
+				newToken(KindOpenBrace, 1),
+				newToken(KindCloseBrace, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindNamespacedMap, 6),
+				newToken(KindOpenBrace, 1),
+				newToken(KindCloseBrace, 1),
+			},
+		},
+		{
+			name: "tagged literals",
+			source: `
+#inst "2020" #my.ns/tag[1]
+			`,
+			expected: []Token{
+				newToken(KindTag, 5),
+				newToken(KindWhitespace, 1),
+				newString(6),
+				newToken(KindWhitespace, 1),
+				newTag(10, 5),
+				newToken(KindOpenBracket, 1),
+				newInteger(1, BaseDecimal),
+				newToken(KindCloseBracket, 1),
+			},
+		},
+		{
+			name: "regex",
+			source: `
+#"[a-z]+\d\"\\" #"\q\u12"
+			`,
+			expected: []Token{
+				newRegex(15),
+				newToken(KindWhitespace, 1),
+				newRegex(9),
+			},
+		},
+		{
+			name: "unterminated regex",
+			source: `
+#"abc\"
+			`,
+			expected: []Token{
+				newRegex(7, literalFlagUnterminated),
+			},
+		},
+		{
+			name: "unknown dispatch",
+			source: `
+#@ #1
+			`,
+			expected: []Token{
+				newToken(KindDispatch, 1),
+				newToken(KindDeref, 1),
+				newToken(KindWhitespace, 1),
+				newToken(KindDispatch, 1),
+				newInteger(1, BaseDecimal),
+			},
+		},
 		{
 			name: "groups",
 			source: `
@@ -475,7 +808,8 @@ a'
 			err := Tokenize(&sc, source)
 			received := sc.Tokens()
 
-			if err != nil {
+			var diags Diagnostics
+			if err != nil && !errors.As(err, &diags) {
 				t.Fatal(err)
 			}
 
@@ -484,6 +818,125 @@ a'
 	}
 }
 
+func TestWhitespace(t *testing.T) {
+	tests := []struct {
+		r          rune
+		whitespace bool
+	}{
+		{'\t', true},
+		{'\v', true},
+		{'\u001C', true},
+		{'\u001F', true},
+		{'\u1680', true},
+		{'\u2000', true},
+		{'\u200A', true},
+		{'\u205F', true},
+		{'\u3000', true},
+		{'\u2028', true},
+		{'\u00A0', false},
+		{'\u2007', false},
+		{'\u202F', false},
+		{'\u0085', false},
+		{'\u200B', false},
+		{'\u200E', false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%U", tt.r), func(t *testing.T) {
+			source := "a" + string(tt.r) + "b"
+			var sc sliceConsumer
+			var diags Diagnostics
+			if err := Tokenize(&sc, source); err != nil && !errors.As(err, &diags) {
+				t.Fatal(err)
+			}
+
+			kind := KindInvalid
+			if tt.whitespace {
+				kind = KindWhitespace
+			}
+			requireEqual(t, 3, len(sc.Tokens()))
+			requireEqual(t, kind, sc.Tokens()[1].Kind())
+		})
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	tests := []struct {
+		source       string
+		namespace    string
+		name         string
+		autoResolved bool
+	}{
+		{"foo", "", "foo", false},
+		{"clojure.core/map", "clojure.core", "map", false},
+		{"clojure.core//", "clojure.core", "/", false},
+		{"/", "", "/", false},
+		{":foo", "", "foo", false},
+		{":ns/foo", "ns", "foo", false},
+		{"::foo", "", "foo", true},
+		{"::alias/foo", "alias", "foo", true},
+		{"#inst", "", "inst", false},
+		{"#my.ns/tag", "my.ns", "tag", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			var sc sliceConsumer
+			if err := Tokenize(&sc, tt.source); err != nil {
+				t.Fatal(err)
+			}
+			token := sc.Tokens()[0]
+			requireEqual(t, len(tt.source), token.Len())
+
+			var namespace, name string
+			var hasNamespace, autoResolved bool
+			switch token.Kind() {
+			case KindKeyword:
+				k := token.Keyword()
+				namespace, name = k.Namespace(tt.source), k.Name(tt.source)
+				hasNamespace, autoResolved = k.HasNamespace(), k.AutoResolved()
+				requireEqual(t, namespace, tt.source[k.NamespaceOffset():k.NamespaceOffset()+k.NamespaceLen()])
+			case KindTag:
+				tag := token.Tag()
+				namespace, name = tag.Namespace(tt.source), tag.Name(tt.source)
+				hasNamespace = tag.HasNamespace()
+				requireEqual(t, namespace, tt.source[tag.NamespaceOffset():tag.NamespaceOffset()+tag.NamespaceLen()])
+			default:
+				s := token.Symbol()
+				namespace, name = s.Namespace(tt.source), s.Name(tt.source)
+				hasNamespace = s.HasNamespace()
+				requireEqual(t, namespace, tt.source[s.NamespaceOffset():s.NamespaceOffset()+s.NamespaceLen()])
+			}
+
+			requireEqual(t, tt.namespace, namespace)
+			requireEqual(t, tt.name, name)
+			requireEqual(t, tt.namespace != "", hasNamespace)
+			requireEqual(t, tt.autoResolved, autoResolved)
+		})
+	}
+}
+
+func TestNewLiteralTokens(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected Token
+	}{
+		{"42", NewIntegerToken(2, BaseDecimal)},
+		{"0x2A", NewIntegerToken(4, BaseHexadecimal)},
+		{`"a\"b"`, NewStringToken(6)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			var sc sliceConsumer
+			if err := Tokenize(&sc, tt.source); err != nil {
+				t.Fatal(err)
+			}
+			requireEqual(t, tt.expected, sc.Tokens()[0])
+		})
+	}
+}
+
 func TestFormat(t *testing.T) {
 	requireEqual(t, "token.Token{Kind:invalid Len:1}", fmt.Sprintf("%+v", newToken(KindInvalid, 1)))
 	requireEqual(t, "{invalid 1}", fmt.Sprintf("%v", newToken(KindInvalid, 1)))
@@ -536,19 +989,23 @@ func TestFormat(t *testing.T) {
 	requireEqual(t, "token.Token{Kind:line-comment Len:4}", fmt.Sprintf("%+v", newToken(KindLineComment, 4)))
 	requireEqual(t, "{line-comment 4}", fmt.Sprintf("%v", newToken(KindLineComment, 4)))
 	requireEqual(t, "{line-comment 4}", fmt.Sprintf("%s", newToken(KindLineComment, 4)))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.Integer{Base:10 EmptyInt:false}} Len:12}", fmt.Sprintf("%+v", newInteger(12, BaseDecimal)))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.Integer{Base:16 EmptyInt:true}} Len:12}", fmt.Sprintf("%+v", newInteger(12, BaseHexadecimal, literalFlagEmptyInt)))
-	requireEqual(t, "{{token.Integer{10 false}} 12}", fmt.Sprintf("%v", newInteger(12, BaseDecimal)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Integer{Base:10 EmptyInt:false BigInt:false InvalidRadix:false}} Len:12}", fmt.Sprintf("%+v", newInteger(12, BaseDecimal)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Integer{Base:16 EmptyInt:true BigInt:false InvalidRadix:false}} Len:12}", fmt.Sprintf("%+v", newInteger(12, BaseHexadecimal, literalFlagEmptyInt)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Integer{Base:0 EmptyInt:false BigInt:true InvalidRadix:true}} Len:12}", fmt.Sprintf("%+v", newInteger(12, 0, literalFlagBigInt, literalFlagInvalidRadix)))
+	requireEqual(t, "{{token.Integer{10 false false false}} 12}", fmt.Sprintf("%v", newInteger(12, BaseDecimal)))
 	requireEqual(t, "{token.Literal 12}", fmt.Sprintf("%s", newInteger(12, BaseDecimal)))
 	requireEqual(t, "{token.Integer}", fmt.Sprintf("%s", newInteger(12, BaseDecimal).Literal()))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.Float{EmptyExponent:false}} Len:11}", fmt.Sprintf("%+v", newFloat(11)))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.Float{EmptyExponent:true}} Len:11}", fmt.Sprintf("%+v", newFloat(11, literalFlagEmptyExponent)))
-	requireEqual(t, "{{token.Float{false}} 11}", fmt.Sprintf("%v", newFloat(11)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Float{EmptyExponent:false BigDecimal:false}} Len:11}", fmt.Sprintf("%+v", newFloat(11)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Float{EmptyExponent:true BigDecimal:false}} Len:11}", fmt.Sprintf("%+v", newFloat(11, literalFlagEmptyExponent)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Float{EmptyExponent:false BigDecimal:true}} Len:11}", fmt.Sprintf("%+v", newFloat(11, literalFlagBigDecimal)))
+	requireEqual(t, "{{token.Float{false false}} 11}", fmt.Sprintf("%v", newFloat(11)))
 	requireEqual(t, "{token.Literal 11}", fmt.Sprintf("%s", newFloat(11)))
 	requireEqual(t, "{token.Float}", fmt.Sprintf("%s", newFloat(11).Literal()))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.String{Unterminated:false}} Len:11}", fmt.Sprintf("%+v", newString(11)))
-	requireEqual(t, "token.Token{Kind:token.Literal{token.String{Unterminated:true}} Len:11}", fmt.Sprintf("%+v", newString(11, literalFlagUnterminated)))
-	requireEqual(t, "{{token.String{false}} 11}", fmt.Sprintf("%v", newString(11)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.String{Unterminated:false InvalidEscape:false}} Len:11}", fmt.Sprintf("%+v", newString(11)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.String{Unterminated:true InvalidEscape:false}} Len:11}", fmt.Sprintf("%+v", newString(11, literalFlagUnterminated)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.String{Unterminated:false InvalidEscape:true InvalidEscapeOffset:3}} Len:11}", fmt.Sprintf("%+v", newInvalidEscapeString(11, 3)))
+	requireEqual(t, "{{token.String{false false}} 11}", fmt.Sprintf("%v", newString(11)))
+	requireEqual(t, "{{token.String{false true}} 11}", fmt.Sprintf("%v", newInvalidEscapeString(11, 3)))
 	requireEqual(t, "{token.Literal 11}", fmt.Sprintf("%s", newString(11)))
 	requireEqual(t, "{token.String}", fmt.Sprintf("%s", newString(11).Literal()))
 	requireEqual(t, "token.Token{Kind:token.Literal{token.Character{MissingCharacter:false}} Len:2}", fmt.Sprintf("%+v", newCharacter(2)))
@@ -556,6 +1013,39 @@ func TestFormat(t *testing.T) {
 	requireEqual(t, "{{token.Character{false}} 2}", fmt.Sprintf("%v", newCharacter(2)))
 	requireEqual(t, "{token.Literal 2}", fmt.Sprintf("%s", newCharacter(2)))
 	requireEqual(t, "{token.Character}", fmt.Sprintf("%s", newCharacter(2).Literal()))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Regex{Unterminated:false}} Len:4}", fmt.Sprintf("%+v", newRegex(4)))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Regex{Unterminated:true}} Len:3}", fmt.Sprintf("%+v", newRegex(3, literalFlagUnterminated)))
+	requireEqual(t, "{{token.Regex{false}} 4}", fmt.Sprintf("%v", newRegex(4)))
+	requireEqual(t, "{token.Literal 4}", fmt.Sprintf("%s", newRegex(4)))
+	requireEqual(t, "{token.Regex}", fmt.Sprintf("%s", newRegex(4).Literal()))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Ratio{}} Len:3}", fmt.Sprintf("%+v", newRatio(3)))
+	requireEqual(t, "{{token.Ratio{}} 3}", fmt.Sprintf("%v", newRatio(3)))
+	requireEqual(t, "{token.Literal 3}", fmt.Sprintf("%s", newRatio(3)))
+	requireEqual(t, "{token.Ratio}", fmt.Sprintf("%s", newRatio(3).Literal()))
+	requireEqual(t, "token.Token{Kind:token.Literal{token.Boolean{}} Len:2}", fmt.Sprintf("%+v", newBoolean(2)))
+	requireEqual(t, "{{token.Boolean{}} 2}", fmt.Sprintf("%v", newBoolean(2)))
+	requireEqual(t, "{token.Boolean}", fmt.Sprintf("%s", newBoolean(2).Literal()))
+	requireEqual(t, "token.Token{Kind:open-set Len:2}", fmt.Sprintf("%+v", newToken(KindOpenSet, 2)))
+	requireEqual(t, "{open-fn 2}", fmt.Sprintf("%v", newToken(KindOpenFn, 2)))
+	requireEqual(t, "{var-quote 2}", fmt.Sprintf("%v", newToken(KindVarQuote, 2)))
+	requireEqual(t, "{discard 2}", fmt.Sprintf("%v", newToken(KindDiscard, 2)))
+	requireEqual(t, "{reader-conditional 2}", fmt.Sprintf("%v", newToken(KindReaderConditional, 2)))
+	requireEqual(t, "{reader-conditional-splicing 3}", fmt.Sprintf("%v", newToken(KindReaderConditionalSplicing, 3)))
+	requireEqual(t, "{symbolic-value 5}", fmt.Sprintf("%v", newToken(KindSymbolicValue, 5)))
+	requireEqual(t, "{namespaced-map 5}", fmt.Sprintf("%v", newToken(KindNamespacedMap, 5)))
+	requireEqual(t, "{tag 5}", fmt.Sprintf("%s", newToken(KindTag, 5)))
+	requireEqual(t, "token.Token{Kind:keyword Len:4}", fmt.Sprintf("%+v", newToken(KindKeyword, 4)))
+	requireEqual(t, "{keyword 4}", fmt.Sprintf("%v", newToken(KindKeyword, 4)))
+	requireEqual(t, "token.Token{Kind:block-comment Len:5}", fmt.Sprintf("%+v", newToken(KindBlockComment, 5)))
+	requireEqual(t, "{comma 1}", fmt.Sprintf("%v", newToken(KindComma, 1)))
+	requireEqual(t, "token.Symbol{HasNamespace:true NamespaceLen:3}", fmt.Sprintf("%+v", newSymbol(7, 3).Symbol()))
+	requireEqual(t, "{false 0}", fmt.Sprintf("%v", newToken(KindSymbol, 3).Symbol()))
+	requireEqual(t, "token.Keyword{AutoResolved:true HasNamespace:true NamespaceLen:2}", fmt.Sprintf("%+v", newKeyword(6, 2, true).Keyword()))
+	requireEqual(t, "{false false 0}", fmt.Sprintf("%v", newKeyword(2, 0, false).Keyword()))
+	requireEqual(t, "token.Tag{HasNamespace:true NamespaceLen:5}", fmt.Sprintf("%+v", newTag(10, 5).Tag()))
+	requireEqual(t, "{false 0}", fmt.Sprintf("%v", newTag(5, 0).Tag()))
+	requireEqual(t, "token.BlockComment{Unterminated:true}", fmt.Sprintf("%+v", newBlockComment(5, true).BlockComment()))
+	requireEqual(t, "{false}", fmt.Sprintf("%v", newBlockComment(5, false).BlockComment()))
 }
 
 func TestPanics(t *testing.T) {
@@ -575,6 +1065,42 @@ func TestPanics(t *testing.T) {
 				_ = newToken(KindOpenParen, 1).Literal()
 			},
 		},
+		{
+			"Symbol called on non-symbol",
+			func() {
+				_ = newToken(KindKeyword, 1).Symbol()
+			},
+		},
+		{
+			"Keyword called on non-keyword",
+			func() {
+				_ = newToken(KindSymbol, 1).Keyword()
+			},
+		},
+		{
+			"Tag called on non-tag",
+			func() {
+				_ = newToken(KindSymbol, 1).Tag()
+			},
+		},
+		{
+			"BlockComment called on non-block-comment",
+			func() {
+				_ = newToken(KindLineComment, 1).BlockComment()
+			},
+		},
+		{
+			"Boolean called on non-boolean literal",
+			func() {
+				_ = newInteger(1, BaseDecimal).Literal().Boolean()
+			},
+		},
+		{
+			"Ratio called on non-ratio literal",
+			func() {
+				_ = newInteger(1, BaseDecimal).Literal().Ratio()
+			},
+		},
 		{
 			"String called on non-string literal",
 			func() {
@@ -593,6 +1119,12 @@ func TestPanics(t *testing.T) {
 				_ = newCharacter(2).Literal().Float()
 			},
 		},
+		{
+			"Regex called on non-regex literal",
+			func() {
+				_ = newString(2).Literal().Regex()
+			},
+		},
 		{
 			"Character called on non-character literal",
 			func() {
@@ -632,6 +1164,35 @@ func newToken(kind Kind, tlen int) Token {
 	}
 }
 
+func newSymbol(tlen int, nsLen int) Token {
+	return Token{
+		kind:  KindSymbol,
+		len:   uint32(tlen),
+		ident: ident{nsLen: uint32(nsLen), flags: identFlagNamespace},
+	}
+}
+
+func newKeyword(tlen int, nsLen int, autoResolved bool) Token {
+	var flags identFlags
+	flags.setIf(nsLen > 0, identFlagNamespace)
+	flags.setIf(autoResolved, identFlagAutoResolved)
+	return Token{
+		kind:  KindKeyword,
+		len:   uint32(tlen),
+		ident: ident{nsLen: uint32(nsLen), flags: flags},
+	}
+}
+
+func newTag(tlen int, nsLen int) Token {
+	var flags identFlags
+	flags.setIf(nsLen > 0, identFlagNamespace)
+	return Token{
+		kind:  KindTag,
+		len:   uint32(tlen),
+		ident: ident{nsLen: uint32(nsLen), flags: flags},
+	}
+}
+
 func newInteger(tlen int, base Base, flags ...literalFlags) Token {
 	return Token{
 		kind: KindLiteral,
@@ -644,6 +1205,17 @@ func newInteger(tlen int, base Base, flags ...literalFlags) Token {
 	}
 }
 
+func newRatio(tlen int) Token {
+	return Token{
+		kind: KindLiteral,
+		len:  uint32(tlen),
+		literal: Literal{
+			kind: LiteralKindRatio,
+			base: BaseDecimal,
+		},
+	}
+}
+
 func newFloat(tlen int, flags ...literalFlags) Token {
 	return Token{
 		kind: KindLiteral,
@@ -666,6 +1238,18 @@ func newString(tlen int, flags ...literalFlags) Token {
 	}
 }
 
+func newInvalidEscapeString(tlen int, offset int, flags ...literalFlags) Token {
+	return Token{
+		kind: KindLiteral,
+		len:  uint32(tlen),
+		literal: Literal{
+			kind:   LiteralKindString,
+			flags:  combineFlags(flags) | literalFlagInvalidEscape,
+			offset: uint32(offset),
+		},
+	}
+}
+
 func newCharacter(tlen int, flags ...literalFlags) Token {
 	return Token{
 		kind: KindLiteral,
@@ -677,6 +1261,17 @@ func newCharacter(tlen int, flags ...literalFlags) Token {
 	}
 }
 
+func newRegex(tlen int, flags ...literalFlags) Token {
+	return Token{
+		kind: KindLiteral,
+		len:  uint32(tlen),
+		literal: Literal{
+			kind:  LiteralKindRegex,
+			flags: combineFlags(flags),
+		},
+	}
+}
+
 func combineFlags(flags []literalFlags) literalFlags {
 	var result literalFlags
 	for _, f := range flags {
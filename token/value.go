@@ -0,0 +1,266 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrInvalidLiteral = errors.New("invalid literal")
+
+const maxEscapeLen = len("u0000")
+
+func (l Integer) Value(src string) (*big.Int, error) {
+	if l.EmptyInt() {
+		return nil, fmt.Errorf("%w %q: missing digits", ErrInvalidLiteral, src)
+	}
+	if l.InvalidRadix() {
+		return nil, fmt.Errorf("%w %q: radix must be between %d and %d", ErrInvalidLiteral, src, minBase, maxBase)
+	}
+
+	digits, neg := trimSign(src)
+	if l.BigInt() {
+		digits = strings.TrimSuffix(digits, "N")
+	}
+	if len(digits) > 1 && digits[0] == '0' && strings.IndexByte("box", digits[1]) != -1 {
+		digits = digits[2:]
+	} else if i := strings.IndexAny(digits, "rR"); i != -1 {
+		digits = digits[i+1:]
+	}
+	digits = strings.ReplaceAll(digits, "_", "")
+
+	v, ok := new(big.Int).SetString(digits, int(l.Base()))
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidLiteral, src)
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func (l Integer) Int64(src string) (int64, error) {
+	v, err := l.Value(src)
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidLiteral, src, strconv.ErrRange)
+	}
+	return v.Int64(), nil
+}
+
+func (l Float) Value(src string) (float64, error) {
+	if l.EmptyExponent() {
+		return 0, fmt.Errorf("%w %q: missing exponent", ErrInvalidLiteral, src)
+	}
+
+	digits := strings.ReplaceAll(src, "_", "")
+	if l.BigDecimal() {
+		digits = strings.TrimSuffix(digits, "M")
+	}
+
+	v, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return v, fmt.Errorf("%w %q: %w", ErrInvalidLiteral, src, strconv.ErrRange)
+		}
+		return 0, fmt.Errorf("%w %q", ErrInvalidLiteral, src)
+	}
+	return v, nil
+}
+
+func (l Ratio) Value(src string) (*big.Rat, error) {
+	digits, neg := trimSign(src)
+	num, denom, _ := strings.Cut(strings.ReplaceAll(digits, "_", ""), "/")
+	n, ok := new(big.Int).SetString(num, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidLiteral, src)
+	}
+	d, ok := new(big.Int).SetString(denom, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidLiteral, src)
+	}
+	if d.Sign() == 0 {
+		return nil, fmt.Errorf("%w %q: zero denominator", ErrInvalidLiteral, src)
+	}
+	v := new(big.Rat).SetFrac(n, d)
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func (l String) Value(src string) (string, error) {
+	if l.Unterminated() || len(src) < 2 {
+		return "", fmt.Errorf("%w %q: unterminated string", ErrInvalidLiteral, src)
+	}
+
+	s := src[1 : len(src)-1]
+	if strings.IndexByte(s, '\\') == -1 {
+		return s, nil
+	}
+
+	b := make([]byte, 0, len(s))
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\\')
+		if i == -1 {
+			b = append(b, s...)
+			break
+		}
+		b = append(b, s[:i]...)
+		s = s[i+1:]
+
+		r, size, ok := scanEscape(s)
+		if !ok {
+			offset := len(src) - len(s) - 2
+			return "", fmt.Errorf("%w %q: invalid escape \\%s at offset %d", ErrInvalidLiteral, src, s[:size], offset)
+		}
+		s = s[size:]
+
+		if utf16.IsSurrogate(r) && strings.HasPrefix(s, `\u`) {
+			if r2, size2, ok := scanEscape(s[1:]); ok {
+				if c := utf16.DecodeRune(r, r2); c != utf8.RuneError {
+					r = c
+					s = s[1+size2:]
+				}
+			}
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b), nil
+}
+
+func (l Character) Value(src string) (rune, error) {
+	name := strings.TrimPrefix(src, "#")
+	if l.MissingCharacter() || len(name) < 2 {
+		return 0, fmt.Errorf("%w %q: missing character", ErrInvalidLiteral, src)
+	}
+
+	name = name[1:]
+	if r, size := utf8.DecodeRuneInString(name); size == len(name) {
+		return r, nil
+	}
+
+	switch name {
+	case "newline":
+		return '\n', nil
+	case "space":
+		return ' ', nil
+	case "tab":
+		return '\t', nil
+	case "backspace":
+		return '\b', nil
+	case "formfeed":
+		return '\f', nil
+	case "return":
+		return '\r', nil
+	}
+
+	switch name[0] {
+	case 'u':
+		if len(name) == 5 {
+			if v, err := strconv.ParseUint(name[1:], 16, 16); err == nil {
+				return rune(v), nil
+			}
+		}
+	case 'o':
+		if len(name) <= 4 {
+			if v, err := strconv.ParseUint(name[1:], 8, 16); err == nil && v <= 0o377 {
+				return rune(v), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w %q: unknown character name", ErrInvalidLiteral, src)
+}
+
+func (l Regex) Value(src string) (string, error) {
+	if l.Unterminated() || len(src) < 3 {
+		return "", fmt.Errorf("%w %q: unterminated regex", ErrInvalidLiteral, src)
+	}
+	return src[2 : len(src)-1], nil
+}
+
+func (l Boolean) Value(src string) (bool, error) {
+	switch src {
+	case "#t", "#true":
+		return true, nil
+	case "#f", "#false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w %q", ErrInvalidLiteral, src)
+	}
+}
+
+func trimSign(s string) (string, bool) {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		return s[1:], s[0] == '-'
+	}
+	return s, false
+}
+
+func scanEscape(s string) (r rune, size int, ok bool) {
+	if len(s) == 0 {
+		return 0, 0, false
+	}
+
+	switch s[0] {
+	case 't':
+		return '\t', 1, true
+	case 'b':
+		return '\b', 1, true
+	case 'n':
+		return '\n', 1, true
+	case 'f':
+		return '\f', 1, true
+	case 'r':
+		return '\r', 1, true
+	case '"':
+		return '"', 1, true
+	case '\\':
+		return '\\', 1, true
+	case 'u':
+		for size = 1; size < 5; size++ {
+			if size >= len(s) || !isHexDigit(s[size]) {
+				return 0, size, false
+			}
+			r = r<<4 | rune(hexValue(s[size]))
+		}
+		return r, size, true
+	}
+
+	if isOctalDigit(s[0]) {
+		for size < 3 && size < len(s) && isOctalDigit(s[size]) {
+			r = r<<3 | rune(s[size]-'0')
+			size++
+		}
+		return r, size, r <= 0o377
+	}
+
+	_, size = utf8.DecodeRuneInString(s)
+	return 0, size, false
+}
+
+func isOctalDigit(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
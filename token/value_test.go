@@ -0,0 +1,233 @@
+package token
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestIntegerValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		err      error
+	}{
+		{"0", "0", nil},
+		{"1_234_567_890", "1234567890", nil},
+		{"0123", "123", nil},
+		{"0b0101_1100", "92", nil},
+		{"0o_777_", "511", nil},
+		{"0xDead_BEEF", "3735928559", nil},
+		{"123456789012345678901234567890", "123456789012345678901234567890", nil},
+		{"-42", "-42", nil},
+		{"+42", "42", nil},
+		{"42N", "42", nil},
+		{"-0x1FN", "-31", nil},
+		{"2r1010", "10", nil},
+		{"36rZZ", "1295", nil},
+		{"16R_ff_", "255", nil},
+		{"-8r17", "-15", nil},
+		{"37r1", "", ErrInvalidLiteral},
+		{"10r", "", ErrInvalidLiteral},
+		{"0x", "", ErrInvalidLiteral},
+		{"0b", "", ErrInvalidLiteral},
+		{"0b__", "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).Integer().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			if err == nil {
+				requireEqual(t, tt.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestIntegerInt64(t *testing.T) {
+	v, err := literalOf(t, "0x7fff_ffff_ffff_ffff").Integer().Int64("0x7fff_ffff_ffff_ffff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireEqual(t, int64(math.MaxInt64), v)
+
+	_, err = literalOf(t, "9223372036854775808").Integer().Int64("9223372036854775808")
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected %v, received %v", strconv.ErrRange, err)
+	}
+}
+
+func TestFloatValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected float64
+		err      error
+	}{
+		{"0.5", 0.5, nil},
+		{"12.3E10", 12.3e10, nil},
+		{"98_76_._54e+5", 9876.54e5, nil},
+		{"00.444441e-123", 0.444441e-123, nil},
+		{"1_234E56", 1234e56, nil},
+		{"-1.5", -1.5, nil},
+		{"+3.0", 3, nil},
+		{"1.5M", 1.5, nil},
+		{"1M", 1, nil},
+		{"-2e3M", -2000, nil},
+		{"1e999", math.Inf(1), strconv.ErrRange},
+		{"1e", 0, ErrInvalidLiteral},
+		{"1.5e+", 0, ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).Float().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			requireEqual(t, tt.expected, v)
+		})
+	}
+}
+
+func TestRatioValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		err      error
+	}{
+		{"1/3", "1/3", nil},
+		{"-22/7", "-22/7", nil},
+		{"+4/2", "2/1", nil},
+		{"1_000/3", "1000/3", nil},
+		{"010/3", "10/3", nil},
+		{"1/010", "1/10", nil},
+		{"08/3", "8/3", nil},
+		{"-0/5", "0/1", nil},
+		{"1/0", "", ErrInvalidLiteral},
+		{"1/00", "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).Ratio().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			if err == nil {
+				requireEqual(t, tt.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestStringValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		err      error
+	}{
+		{`""`, "", nil},
+		{`"plain"`, "plain", nil},
+		{"\"multi\nline\"", "multi\nline", nil},
+		{`"\t\b\n\f\r\"\\"`, "\t\b\n\f\r\"\\", nil},
+		{`"㉅x"`, "㉅x", nil},
+		{`"\uD83D\uDE00"`, "\U0001F600", nil},
+		{`"\uD83D"`, "�", nil},
+		{`"\0\101\377\1234"`, "\x00AÿS4", nil},
+		{`"\400"`, "", ErrInvalidLiteral},
+		{`"\q"`, "", ErrInvalidLiteral},
+		{`"\'"`, "", ErrInvalidLiteral},
+		{`"\u12"`, "", ErrInvalidLiteral},
+		{`"\u12g4"`, "", ErrInvalidLiteral},
+		{`"abc`, "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).String().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			requireEqual(t, tt.expected, v)
+		})
+	}
+}
+
+func TestCharacterValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected rune
+		err      error
+	}{
+		{`\a`, 'a', nil},
+		{`\\`, '\\', nil},
+		{`\(`, '(', nil},
+		{`\é`, 'é', nil},
+		{`\u`, 'u', nil},
+		{`\o`, 'o', nil},
+		{`\newline`, '\n', nil},
+		{`\space`, ' ', nil},
+		{`\tab`, '\t', nil},
+		{`\backspace`, '\b', nil},
+		{`\formfeed`, '\f', nil},
+		{`\return`, '\r', nil},
+		{`\ሴ`, 'ሴ', nil},
+		{`\o101`, 'A', nil},
+		{`\o7`, '\a', nil},
+		{`\o400`, 0, ErrInvalidLiteral},
+		{`\u12`, 0, ErrInvalidLiteral},
+		{`\auml`, 0, ErrInvalidLiteral},
+		{`\`, 0, ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).Character().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			requireEqual(t, tt.expected, v)
+		})
+	}
+}
+
+func literalOf(tb testing.TB, source string) Literal {
+	tb.Helper()
+	var sc sliceConsumer
+	var diags Diagnostics
+	if err := Tokenize(&sc, source); err != nil && !errors.As(err, &diags) {
+		tb.Fatal(err)
+	}
+	tokens := sc.Tokens()
+	if len(tokens) != 1 || tokens[0].Len() != len(source) {
+		tb.Fatalf("expected a single token, received %v", tokens)
+	}
+	return tokens[0].Literal()
+}
+
+func TestRegexValue(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		err      error
+	}{
+		{`#""`, "", nil},
+		{`#"[a-z]+"`, "[a-z]+", nil},
+		{`#"\d+\.\"\\"`, `\d+\.\"\\`, nil},
+		{`#"abc`, "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			v, err := literalOf(t, tt.source).Regex().Value(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, received %v", tt.err, err)
+			}
+			requireEqual(t, tt.expected, v)
+		})
+	}
+}